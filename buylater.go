@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"calculator/calc"
+)
+
+// buyLaterMonths is set by the --buy-later flag
+var buyLaterMonths int
+
+// buyLaterResult is the buy-later path's net worth at one period
+type buyLaterResult struct {
+	months   int
+	buyNow   float64 // Net worth buying today
+	buyLater float64 // Net worth renting for buyLaterMonths, then buying
+}
+
+// shiftRates returns a year-by-year rate schedule starting years later; the last rate still applies to all remaining years
+func shiftRates(rates []float64, years int) []float64 {
+	if years < len(rates) {
+		return rates[years:]
+	}
+	return []float64{rates[len(rates)-1]}
+}
+
+// calculateBuyLater compares buying today against renting for delay months and then buying, at each of the given horizons
+// The renter invests the would-be downpayment and cost savings; at the handoff the grown savings fund the purchase at the
+// appreciated price, and from then on the difference between the two paths' monthly costs is invested (or drawn down)
+// Also returns the cash needed at the later purchase (downpayment and upfront costs)
+// The later purchase is projected from a copy of the config, leaving the current config and monthly untouched
+func calculateBuyLater(delay int, horizons []int) ([]buyLaterResult, float64) {
+	var results []buyLaterResult
+	returns := rentingAnnualReturns(&config)
+	savingsAtPurchase := calculateRentingNetWorth(delay)
+	for _, months := range horizons {
+		_, _, buyNow := calculateNetWorth(months)
+		results = append(results, buyLaterResult{months: months, buyNow: buyNow})
+	}
+
+	// Buy at the appreciated price with the same loan-to-value; fixed-dollar costs grow with inflation
+	later := config
+	priceScale := calc.AppreciatedValue(&config, config.PurchasePrice, delay) / config.PurchasePrice
+	costScale := calc.InflationFactor(&config, delay)
+	later.PurchasePrice *= priceScale
+	later.LoanAmount *= priceScale
+	later.SecondLoanAmount *= priceScale
+	later.AnnualInsurance *= priceScale
+	later.AnnualTaxes *= costScale
+	later.MonthlyExpenses *= costScale
+	later.UtilitiesDiff *= costScale
+	later.ClosingCosts *= costScale
+	later.MovingCost *= costScale
+
+	// The schedules and one-off costs restart from the purchase date
+	later.AppreciationRates = shiftRates(config.AppreciationRates, delay/12)
+	later.InflationRates = shiftRates(config.InflationRates, delay/12)
+	later.InvestmentReturnRates = shiftRates(config.InvestmentReturnRates, delay/12)
+	later.OneTimeCosts = make(map[int]float64)
+	for month, amount := range config.OneTimeCosts {
+		if month > delay {
+			later.OneTimeCosts[month-delay] = amount
+		}
+	}
+	laterCosts := calc.Project(&later)
+
+	purchaseCash := later.Downpayment + calc.UpfrontBuyingCosts(&later)
+	for i := range results {
+		_, _, homeNetWorth := calc.NetWorth(&later, laterCosts, results[i].months-delay)
+
+		savings := savingsAtPurchase - purchaseCash
+		for j := 0; j < results[i].months-delay; j++ {
+			year := (delay + j) / 12
+			if year >= len(returns) {
+				year = len(returns) - 1
+			}
+			savings += monthly.BuyingCosts[delay+j] - laterCosts.BuyingCosts[j]
+			savings *= 1 + calc.AfterTaxMonthlyReturn(&config, returns[year])
+		}
+		results[i].buyLater = homeNetWorth + savings
+	}
+	return results, purchaseCash
+}
+
+// displayBuyLater displays net worth buying today vs renting for delay months and then buying
+func displayBuyLater(delay int) {
+	var horizons []int
+	for _, period := range getPeriods(config.TotalMonths, config.Include30Year) {
+		if period.months > delay {
+			horizons = append(horizons, period.months)
+		}
+	}
+	if len(horizons) == 0 {
+		fmt.Printf("\nBuy-later skipped: --buy-later %d is beyond every period shown\n", delay)
+		return
+	}
+
+	laterPrice := calc.AppreciatedValue(&config, config.PurchasePrice, delay)
+	savingsAtPurchase := calculateRentingNetWorth(delay)
+	results, purchaseCash := calculateBuyLater(delay, horizons)
+
+	later := "BUY IN " + periodLabel(delay)
+	rows := [][]string{{"Period", "BUY NOW", later, "LATER - NOW"}}
+	for _, result := range results {
+		rows = append(rows, []string{
+			"NW " + periodLabel(result.months),
+			formatCurrency(deflate(result.buyNow, result.months)),
+			formatCurrency(deflate(result.buyLater, result.months)),
+			formatCurrency(deflate(result.buyLater-result.buyNow, result.months)),
+		})
+	}
+
+	notes := fmt.Sprintf("Note: '%s' rents for %s, investing the would-be downpayment and cost savings (%s by then), then buys at the appreciated price of %s with the same loan-to-value and rate, paying %s in downpayment and upfront costs. After that, the difference in monthly costs between the two paths is invested (or drawn down). 'LATER - NOW': Positive values mean waiting wins, negative values mean buying now wins.",
+		later, periodLabel(delay), formatCurrency(savingsAtPurchase), formatCurrency(laterPrice), formatCurrency(purchaseCash))
+	if shortfall := purchaseCash - savingsAtPurchase; shortfall > 0 {
+		notes += fmt.Sprintf(" Savings fall %s short of that cash; the shortfall is treated as a negative investment balance.", formatCurrency(shortfall))
+	}
+	notes += realDollarsNote()
+	displayTable("BUY NOW VS BUY LATER", rows, notes, false)
+
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
+	// Judge at the same horizon as the main verdict, or at the last period when the purchase comes after it
+	verdictAt := verdictMonths()
+	if verdictAt <= delay {
+		verdictAt = results[len(results)-1].months
+	}
+	atVerdict, _ := calculateBuyLater(delay, []int{verdictAt})
+	difference := deflate(atVerdict[0].buyLater-atVerdict[0].buyNow, verdictAt)
+	verdict := fmt.Sprintf("buying now comes out ahead by %s at %s", formatCurrency(math.Abs(difference)), periodLabel(verdictAt))
+	if difference > 0 {
+		verdict = fmt.Sprintf("waiting %s to buy comes out ahead by %s at %s", periodLabel(delay), formatCurrency(difference), periodLabel(verdictAt))
+	}
+	fmt.Printf("\n  %s: %s\n", labelStyle.Render("Buy later"), verdict)
+}
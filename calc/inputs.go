@@ -0,0 +1,157 @@
+// Package calc projects the finances of buying vs renting a home, or selling vs keeping one
+//
+// Fill in the primary Inputs, call Project to get the month-by-month Results, then read net worth
+// at any horizon with NetWorth, RentingNetWorth, SellNetWorth or KeepNetWorth. Project fills in the
+// derived Inputs (loan payments, equity, starting monthly costs) through Normalize
+package calc
+
+// Inputs holds all input parameters
+type Inputs struct {
+	// Scenario
+	SellVsKeep bool // SELL vs KEEP an asset held today; false means BUY vs RENT a new purchase
+
+	// Economic
+	InflationRate float64
+	Include30Year bool
+
+	// Rate schedules and projection settings
+	AppreciationRates     []float64       // Annual appreciation rates by year
+	InflationRates        []float64       // Annual inflation rates by year
+	InvestmentReturnRates []float64       // Annual investment return rates by year
+	TaxFreeLimits         []float64       // Tax-free capital gains limits by year
+	OneTimeCosts          map[int]float64 // One-off expenses by month (1-based), e.g. a new roof
+	HorizonMonths         int             // Projection horizon (extended to the loan term when longer)
+	MonthlyAppreciation   bool            // Appreciation compounds monthly at the nominal rate / 12 instead of by whole years
+	InflationAtStart      bool            // --inflation-timing start: each year's rate applies from its first month, year one included; false is the original anniversary schedule
+
+	// Buying/Asset
+	PurchasePrice       float64 // Original purchase price (for capital gains)
+	CurrentMarketValue  float64 // Current value (for SELL vs KEEP)
+	LoanAmount          float64 // Loan at purchase; for SELL vs KEEP, Normalize sets it to today's balance of OriginalLoanAmount
+	OriginalLoanAmount  float64 // SELL vs KEEP: the loan as originally taken out
+	AnnualRate          float64 // Annual rate on the first loan
+	LoanMonths          int     // Loan term (the original term for SELL vs KEEP)
+	RemainingLoanMonths int     // SELL vs KEEP: months left on the loan today
+	BalloonMonths       int     // Balloon date of a BUY vs RENT loan amortized over LoanMonths (0 = fully amortizing)
+	AnnualInsurance     float64
+	AnnualTaxes         float64
+	MonthlyExpenses     float64
+	UtilitiesDiff       float64   // Extra monthly utilities when owning vs renting (can be negative)
+	MaintenancePct      float64   // Annual maintenance as a percent of the (appreciating) asset value
+	ValueBasedEscrow    bool      // Whether tax & insurance scale with the asset value instead of inflation
+	ReassessYears       int       // Years between tax reassessments to the asset value (0 = inflation only)
+	ReassessCapPct      float64   // Annual growth cap (%) on tax & insurance between reassessments
+	LumpSumAmount       float64   // One-time prepayment toward loan principal
+	LumpSumMonth        int       // Month (1-based) in which the lump sum is paid
+	BuydownRates        []float64 // Temporary rate reductions by year from a builder/seller-paid buydown (e.g., 2-1 = [2, 1])
+	SecondLoanAmount    float64   // Second mortgage or HELOC balance, amortized alongside the first loan
+	SecondLoanRate      float64   // Annual rate on the second loan
+	SecondLoanMonths    int       // Second loan term (remaining term for SELL vs KEEP)
+	ClosingCosts        float64   // Upfront purchase costs beyond the downpayment (title, escrow, inspection)
+	LoanPoints          float64   // Discount points / origination fee as a percentage of the loan amount
+	MarginalTaxRate     float64   // Income tax rate (%) at which itemized deductions save tax (0 = no deduction)
+	PropertyTax         float64   // Annual property tax within tax & insurance, deductible when itemizing
+	StateIncomeTax      float64   // Annual state and local income tax, deductible when itemizing within the SALT cap, inflated
+	SaltCap             float64   // Annual cap on deductible property tax plus state income tax (SALT limit, 0 = no cap), not inflated
+	StandardDeduction   float64   // Annual standard deduction, inflated
+	OtherItemized       float64   // Annual itemized deductions besides mortgage interest and state and local taxes, inflated
+	IncludeRentalIncome bool      // Whether the asset is rented out if keeping (SELL vs KEEP)
+	MonthlyRentalIncome float64   // Gross monthly rent received if keeping, inflated annually
+	VacancyRate         float64   // Percent of gross rent lost to vacancy
+	ManagementFeePct    float64   // Property-management fee as a percent of collected rent
+	DepreciableBasis    float64   // Structure value depreciated while renting the asset out (excludes land)
+	RentalIncomeTaxRate float64   // Income tax rate on net rental income after deductible costs and depreciation
+
+	// Renting
+	RentDeposit          float64
+	RentBrokerFee        float64 // Non-recoverable broker fee paid at move-in
+	FirstLastMonth       bool    // Whether last month's rent is prepaid at move-in along with the first
+	IncludeRentingSell   bool    // Whether selling means renting (SELL vs KEEP)
+	MovingCost           float64 // Cost of each move in today's dollars, inflated (buyers move once, renters every RentMoveMonths)
+	RentMoveMonths       int     // How often renters move (0 = only at the start)
+	MonthlyRent          float64
+	PriceToRent          float64 // Purchase price / annual rent, used to estimate a blank monthly rent
+	RentEstimated        bool    // Monthly rent was derived from PriceToRent
+	RentStep             float64 // Rent increase at each yearly lease renewal, replacing inflation on rent (0 = none)
+	RentStepPct          bool    // RentStep is a percent rather than dollars
+	AnnualRentCosts      float64
+	OtherAnnualCosts     float64
+	InvestmentReturnRate float64
+	InvestmentTaxRate    float64 // Tax on investment gains and dividends, applied as a haircut on the return rate
+	DiscountRate         float64 // Annual rate for NPV discounting (0 = disabled)
+
+	// Selling
+	IncludeSelling     bool
+	AgentCommission    float64
+	StagingCosts       float64
+	TransferTaxPct     float64 // Real-estate transfer tax as a percent of sale price
+	SellerConcessions  float64 // Fixed concessions given to the buyer at sale
+	PrepayPenaltyPct   float64 // Penalty as a percent of the loan payoff when sold early
+	PrepayPenaltyYears float64 // Years (from purchase, or from today for SELL vs KEEP) the penalty applies
+	CapitalGainsTax    float64
+	ShortTermRate      float64 // Tax rate on gains from sales held 12 months or less (ordinary income)
+	PrimaryResidence   bool    // Whether the home is a primary residence (required for the tax-free exclusion)
+	MonthsOccupied     int     // Months already lived in the home as of today
+
+	// Derived by Normalize from the fields above
+	Downpayment             float64 // Cash put in at purchase (BUY vs RENT) or today's equity (SELL vs KEEP)
+	TotalMonths             int     // Months of first-loan payments from today: the term, balloon date or remaining term
+	BalloonAmortMonths      int     // Amortization schedule of a balloon loan; TotalMonths is then the balloon date (0 = fully amortizing)
+	MonthlyRate             float64
+	MonthlyLoanPayment      float64
+	SecondLoanPayment       float64 // Fixed monthly payment on the second loan
+	TotalMonthlyBuyingCost  float64 // First month's cost of owning
+	TotalMonthlyRentingCost float64 // First month's cost of renting
+}
+
+// Normalize derives the loan schedule, equity and first month's costs from the primary inputs
+// Project calls it, so it's only needed to read the derived fields before projecting; repeating it is harmless
+func Normalize(cfg *Inputs) {
+	cfg.TotalMonths = 0
+	cfg.BalloonAmortMonths = 0
+	cfg.MonthlyRate = 0
+	cfg.MonthlyLoanPayment = 0
+	if cfg.SellVsKeep {
+		cfg.LoanAmount = 0
+		if cfg.OriginalLoanAmount > 0 {
+			cfg.MonthlyRate = cfg.AnnualRate / 100 / 12
+			originalPayment := MonthlyPayment(cfg.OriginalLoanAmount, cfg.MonthlyRate, cfg.LoanMonths)
+
+			// Simulate the payments made so far to get today's balance
+			balance := cfg.OriginalLoanAmount
+			for i := 0; i < cfg.LoanMonths-cfg.RemainingLoanMonths; i++ {
+				interestPayment := balance * cfg.MonthlyRate
+				principalPayment := originalPayment - interestPayment
+				balance -= principalPayment
+			}
+
+			// Project the remaining term, with the payment recalculated on the remaining balance
+			cfg.LoanAmount = balance
+			cfg.TotalMonths = cfg.RemainingLoanMonths
+			cfg.MonthlyLoanPayment = MonthlyPayment(balance, cfg.MonthlyRate, cfg.RemainingLoanMonths)
+		}
+		cfg.Downpayment = cfg.CurrentMarketValue - cfg.LoanAmount - cfg.SecondLoanAmount
+	} else {
+		if cfg.LoanAmount > 0 {
+			// A balloon loan pays on the full schedule but the remaining balance comes due at the balloon date
+			cfg.TotalMonths = cfg.LoanMonths
+			if cfg.BalloonMonths > 0 {
+				cfg.BalloonAmortMonths = cfg.LoanMonths
+				cfg.TotalMonths = cfg.BalloonMonths
+			}
+			cfg.MonthlyRate = cfg.AnnualRate / 100 / 12
+			cfg.MonthlyLoanPayment = MonthlyPayment(cfg.LoanAmount, cfg.MonthlyRate, AmortizationMonths(cfg))
+		}
+		cfg.Downpayment = cfg.PurchasePrice - cfg.LoanAmount - cfg.SecondLoanAmount
+	}
+
+	cfg.SecondLoanPayment = 0
+	if cfg.SecondLoanAmount > 0 {
+		cfg.SecondLoanPayment = MonthlyPayment(cfg.SecondLoanAmount, cfg.SecondLoanRate/100/12, cfg.SecondLoanMonths)
+	}
+
+	monthlyRecurringExpenses := ((cfg.AnnualInsurance + cfg.AnnualTaxes) / 12) + cfg.MonthlyExpenses + cfg.UtilitiesDiff
+	cfg.TotalMonthlyBuyingCost = cfg.MonthlyLoanPayment + cfg.SecondLoanPayment + monthlyRecurringExpenses + MonthlyMaintenance(cfg, 0)
+	monthlyRentingExpenses := (cfg.AnnualRentCosts / 12) + (cfg.OtherAnnualCosts / 12)
+	cfg.TotalMonthlyRentingCost = cfg.MonthlyRent + monthlyRentingExpenses
+}
@@ -0,0 +1,221 @@
+package calc
+
+import "math"
+
+// Primary-residence exclusion: lived in the home for 2 of the 5 years before the sale
+const (
+	ExclusionWindowMonths   = 60
+	ExclusionRequiredMonths = 24
+)
+
+// OccupiedMonthsBeforeSale returns how many of the 60 months before a sale in the given month were lived in
+// The owner keeps living there until the sale, except when keeping means renting the asset out
+func OccupiedMonthsBeforeSale(cfg *Inputs, months int) int {
+	if cfg.IncludeRentalIncome {
+		// Occupancy ended today, so only the part of it within the window counts
+		return max(0, min(cfg.MonthsOccupied, ExclusionWindowMonths-months))
+	}
+	return min(cfg.MonthsOccupied+months, ExclusionWindowMonths)
+}
+
+// QualifiesForExclusion reports whether the tax-free gains limit applies to a sale in the given month
+func QualifiesForExclusion(cfg *Inputs, months int) bool {
+	return cfg.PrimaryResidence && OccupiedMonthsBeforeSale(cfg, months) >= ExclusionRequiredMonths
+}
+
+// ShortTermHoldingMonths is the longest holding period taxed at the short-term rate
+const ShortTermHoldingMonths = 12
+
+// CapitalGainsRate returns the tax rate for a sale in the given month
+// BUY vs RENT holds from purchase, so early sales are short-term; an asset kept in SELL vs KEEP is already held long-term
+func CapitalGainsRate(cfg *Inputs, months int) float64 {
+	if !cfg.SellVsKeep && months <= ShortTermHoldingMonths {
+		return cfg.ShortTermRate
+	}
+	return cfg.CapitalGainsTax
+}
+
+// SellingCosts returns the total cost of selling at salePrice (all deductible from capital gains)
+func SellingCosts(cfg *Inputs, salePrice float64) float64 {
+	agentFee := salePrice * (cfg.AgentCommission / 100)
+	return agentFee + cfg.StagingCosts + TransferTax(cfg, salePrice) + cfg.SellerConcessions
+}
+
+// TransferTax returns the real-estate transfer tax due on a sale at salePrice
+func TransferTax(cfg *Inputs, salePrice float64) float64 {
+	return salePrice * (cfg.TransferTaxPct / 100)
+}
+
+// SaleProceeds calculates the net proceeds from selling at a given time
+func SaleProceeds(cfg *Inputs, r *Results, months int) (salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds float64) {
+	// Calculate asset value (sale price) by compounding appreciation rates from today's value
+	salePrice = AppreciatedValue(cfg, StartingAssetValue(cfg), months)
+
+	// Combine agent commission, staging costs, transfer tax and concessions
+	totalSellingCosts = SellingCosts(cfg, salePrice)
+
+	// Get remaining loan balance
+	monthIndex := months - 1
+	loanPayoff = r.LoanBalance[monthIndex]
+
+	// Calculate capital gains (selling costs are deductible)
+	capitalGains = salePrice - cfg.PurchasePrice - totalSellingCosts
+
+	// Get tax-free limit for the ownership year the sale falls in, matching the appreciation rate
+	// index used for that year: months 1-12 are year 1 (index 0), months 13-24 are year 2 (index 1), etc.
+	taxFreeLimit := RateForYear(cfg.TaxFreeLimits, max(months-1, 0)/12)
+	if !QualifiesForExclusion(cfg, months) {
+		taxFreeLimit = 0
+	}
+
+	// Calculate taxable gains (after exemption)
+	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
+
+	// Calculate tax on gains
+	taxOnGains = taxableGains * (CapitalGainsRate(cfg, months) / 100)
+
+	// Depreciation taken while renting the asset out lowers the basis; that part of the gain is recaptured at a flat rate
+	depreciation := AccumulatedDepreciation(cfg, months)
+	if recaptured := math.Min(depreciation, capitalGains+depreciation); recaptured > 0 {
+		taxOnGains += recaptured * DepreciationRecaptureRate / 100
+	}
+
+	// Calculate net proceeds
+	netProceeds = salePrice - totalSellingCosts - loanPayoff - taxOnGains - PrepayPenalty(cfg, months, loanPayoff)
+
+	return
+}
+
+// PrepayPenalty returns the prepayment penalty for paying off loanPayoff when selling after the given months
+func PrepayPenalty(cfg *Inputs, months int, loanPayoff float64) float64 {
+	if float64(months) >= cfg.PrepayPenaltyYears*12 {
+		return 0
+	}
+	return loanPayoff * cfg.PrepayPenaltyPct / 100
+}
+
+// NetWorth calculates the asset value, total expenditure, and net worth for a given time period
+// Uses the projection's monthly buying costs and loan balances
+func NetWorth(cfg *Inputs, r *Results, months int) (float64, float64, float64) {
+	// Calculate asset value by compounding each year's appreciation rate
+	assetValue := AppreciatedValue(cfg, cfg.PurchasePrice, months)
+
+	// Calculate total expenditure by summing monthly costs from array
+	totalExpenditure := cfg.Downpayment + UpfrontBuyingCosts(cfg)
+	for i := 0; i < months; i++ {
+		totalExpenditure += r.BuyingCosts[i]
+	}
+
+	// Calculate net worth
+	var netWorth float64
+	if cfg.IncludeSelling {
+		// If selling is enabled, use net proceeds after selling costs
+		_, _, _, _, _, netProceeds := SaleProceeds(cfg, r, months)
+		netWorth = netProceeds
+	} else {
+		// Otherwise, just asset value minus loan balance
+		monthIndex := months - 1
+		loanBalance := r.LoanBalance[monthIndex]
+		netWorth = assetValue - loanBalance
+	}
+
+	return assetValue, totalExpenditure, netWorth
+}
+
+// RentingNetWorth calculates renting net worth with a base monthly rent and year-by-year annual returns
+// annualReturns[year] applies to that year; the last entry applies to all remaining years
+func RentingNetWorth(cfg *Inputs, r *Results, months int, monthlyRent float64, annualReturns []float64) float64 {
+	// Start with the buyer's upfront cash (downpayment, closing costs and points) minus deposit as initial investment
+	investmentValue := cfg.Downpayment + UpfrontBuyingCosts(cfg) - cfg.RentDeposit - UpfrontRentingCosts(cfg)
+
+	// For each month: calculate savings, add to investment, grow investment
+	for i := 0; i < months; i++ {
+		year := i / 12
+		if year >= len(annualReturns) {
+			year = len(annualReturns) - 1
+		}
+		monthlyInvestmentRate := AfterTaxMonthlyReturn(cfg, annualReturns[year])
+
+		// Adjust renting cost for the candidate rent (inflated or stepped like the base rent)
+		rentAdjustment := (monthlyRent - cfg.MonthlyRent) * CostInflationFactor(cfg, i)
+		if RentStepped(cfg) {
+			rentAdjustment = RentForMonth(cfg, monthlyRent, i) - RentForMonth(cfg, cfg.MonthlyRent, i)
+		}
+
+		// Monthly savings = buying cost - renting cost
+		monthlySavings := r.BuyingCosts[i] - (r.RentingCosts[i] + rentAdjustment)
+
+		// Add savings to investment
+		investmentValue += monthlySavings
+
+		// Apply monthly growth
+		investmentValue *= (1 + monthlyInvestmentRate)
+	}
+
+	// Add back 75% of deposit and any prepaid last month (recoverable)
+	return investmentValue + RecoverableRentingCosts(cfg)
+}
+
+// SellNetWorth calculates net worth if selling at month 0 and investing proceeds
+func SellNetWorth(cfg *Inputs, r *Results, months int) float64 {
+	// Calculate net proceeds from selling now
+	salePrice := cfg.CurrentMarketValue
+	totalSellingCosts := SellingCosts(cfg, salePrice)
+	loanPayoff := cfg.LoanAmount + cfg.SecondLoanAmount
+	// Capital gains with selling costs deducted
+	capitalGains := salePrice - cfg.PurchasePrice - totalSellingCosts
+	// Use first tax-free limit (selling now = year 0)
+	taxFreeLimit := cfg.TaxFreeLimits[0]
+	if !QualifiesForExclusion(cfg, 0) {
+		taxFreeLimit = 0
+	}
+	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
+	taxOnGains := taxableGains * (cfg.CapitalGainsTax / 100)
+	netProceeds := salePrice - totalSellingCosts - loanPayoff - taxOnGains - PrepayPenalty(cfg, 0, loanPayoff)
+
+	// Check if we need to account for renting
+	if cfg.IncludeRentingSell {
+		// Start investment with net proceeds minus rental deposit and move-in costs
+		investmentValue := netProceeds - cfg.RentDeposit - UpfrontRentingCosts(cfg)
+
+		// For each month: subtract rental costs, grow investment
+		for i := 0; i < months; i++ {
+			monthlyInvestmentRate := AfterTaxMonthlyReturn(cfg, RateForYear(cfg.InvestmentReturnRates, i/12))
+
+			// Subtract renting costs
+			investmentValue -= r.RentingCosts[i]
+
+			// Apply monthly growth
+			investmentValue *= (1 + monthlyInvestmentRate)
+		}
+
+		// Add back 75% of rental deposit and any prepaid last month (recoverable)
+		return investmentValue + RecoverableRentingCosts(cfg)
+	} else {
+		// Just invest the proceeds without rental costs
+		investmentValue := netProceeds
+
+		// Simple monthly compounding
+		for i := 0; i < months; i++ {
+			investmentValue *= (1 + AfterTaxMonthlyReturn(cfg, RateForYear(cfg.InvestmentReturnRates, i/12)))
+		}
+
+		return investmentValue
+	}
+}
+
+// KeepNetWorth calculates net worth if keeping the asset and selling at future point
+func KeepNetWorth(cfg *Inputs, r *Results, months int) float64 {
+	// Get net proceeds from selling at this future point
+	// This accounts for appreciation, selling costs, loan payoff, and capital gains tax
+	_, _, _, _, _, netProceeds := SaleProceeds(cfg, r, months)
+
+	// Get net position from pre-calculated arrays
+	monthIndex := months - 1
+	if monthIndex < 0 {
+		monthIndex = 0
+	}
+	netPosition := r.KeepNetPosition[monthIndex]
+
+	return netProceeds + netPosition
+}
@@ -0,0 +1,58 @@
+package calc
+
+import "testing"
+
+// TestSaleProceedsTaxFreeLimitYear locks which year's tax-free limit a sale uses:
+// months 1-12 are ownership year 1, months 13-24 year 2, and so on, matching the appreciation applied through that year
+func TestSaleProceedsTaxFreeLimitYear(t *testing.T) {
+	cfg := sellVsKeepInputs()
+	cfg.OriginalLoanAmount = 0
+	cfg.AgentCommission = 0
+	cfg.StagingCosts = 0
+	cfg.AppreciationRates = []float64{10}
+	cfg.TaxFreeLimits = []float64{900000, 800000, 0}
+	r := Project(&cfg)
+
+	tests := []struct {
+		months int
+		limit  float64
+	}{
+		{1, 900000},
+		{12, 900000},
+		{13, 800000},
+		{24, 800000},
+		{25, 0},
+		{36, 0},
+	}
+	for _, tt := range tests {
+		_, _, _, capitalGains, taxOnGains, _ := SaleProceeds(&cfg, r, tt.months)
+		want := max(0, capitalGains-tt.limit) * cfg.CapitalGainsTax / 100
+		if !approxEqual(taxOnGains, want) {
+			t.Errorf("SaleProceeds at %d months: tax = %.2f, want %.2f (gains %.2f less the %.0f limit)",
+				tt.months, taxOnGains, want, capitalGains, tt.limit)
+		}
+	}
+}
+
+// TestSaleProceedsAppreciationYear checks the sale price at the same boundaries: whole years compound, partial years pro-rate
+func TestSaleProceedsAppreciationYear(t *testing.T) {
+	cfg := sellVsKeepInputs()
+	cfg.OriginalLoanAmount = 0
+	cfg.AppreciationRates = []float64{10, 20, 0}
+	r := Project(&cfg)
+
+	tests := []struct {
+		months int
+		price  float64
+	}{
+		{12, 2200000},
+		{24, 2640000},
+		{36, 2640000},
+	}
+	for _, tt := range tests {
+		salePrice, _, _, _, _, _ := SaleProceeds(&cfg, r, tt.months)
+		if !approxEqual(salePrice, tt.price) {
+			t.Errorf("SaleProceeds at %d months: sale price = %.2f, want %.2f", tt.months, salePrice, tt.price)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package calc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// amountSuffixes maps amount suffixes to multipliers, longest suffix first
+var amountSuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"crore", 10000000.0},
+	{"lakh", 100000.0},
+	{"lcr", 1000000000000.0},
+	{"cr", 10000000.0},
+	{"k", 1000.0},
+	{"m", 1000000.0},
+	{"b", 1000000000.0},
+	{"t", 1000000000000.0},
+	{"l", 100000.0},
+}
+
+// ParseAmount parses currency amounts with k, M, B, T suffixes (and L/lakh, Cr/crore, LCr for lakh crore)
+// Returns 0 for empty input
+// Also handles % sign (strips it out)
+func ParseAmount(input string) (float64, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	// Handle empty input - default to 0
+	if input == "" {
+		return 0, nil
+	}
+
+	// Remove % sign if present (for percentage inputs like "-10%")
+	input = strings.TrimSuffix(input, "%")
+	input = strings.TrimSpace(input)
+
+	// Check for suffix (longer suffixes first so "cr" isn't mistaken for another suffix)
+	multiplier := 1.0
+	numStr := input
+
+	for _, s := range amountSuffixes {
+		if strings.HasSuffix(input, s.suffix) {
+			multiplier = s.multiplier
+			numStr = strings.TrimSuffix(input, s.suffix)
+			break
+		}
+	}
+
+	// Strip thousands separators (commas and spaces) from the numeric part
+	numStr = strings.ReplaceAll(numStr, ",", "")
+	numStr = strings.ReplaceAll(numStr, " ", "")
+
+	// Parse the numeric part
+	value, err := strconv.ParseFloat(numStr, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return value * multiplier, nil
+}
+
+// ParseAppreciationRates parses comma-separated appreciation rates
+// Returns array where each entry corresponds to a year, with the last entry applying to all future years
+// Every comma separates entries, so a number with thousands separators must be quoted, e.g. "1,250,000",0
+func ParseAppreciationRates(input string) ([]float64, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return []float64{0}, nil
+	}
+
+	parts, err := SplitList(input)
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]float64, 0, len(parts))
+
+	for _, part := range parts {
+		rate, err := ParseAmount(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate '%s': %v", strings.TrimSpace(part), err)
+		}
+		rates = append(rates, rate)
+	}
+
+	if len(rates) == 0 {
+		return []float64{0}, nil
+	}
+
+	return rates, nil
+}
+
+// SplitList splits a comma-separated list, keeping commas inside double quotes and dropping the quotes
+// List inputs use it so amounts with thousands separators can be quoted, e.g., "1,250,000",0
+func SplitList(input string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+	quoted := false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", input)
+	}
+	return append(parts, part.String()), nil
+}
+
+// ParseDuration parses duration strings like "5y6m", "30y", "2.5y", "6m", or a bare "30" (years)
+func ParseDuration(duration string) (int, error) {
+	duration = strings.ToLower(strings.TrimSpace(duration))
+	years := 0.0
+	months := 0
+
+	// A bare number is treated as years
+	if duration != "" && !strings.ContainsAny(duration, "ym") {
+		duration += "y"
+	}
+
+	// Find 'y' for years (decimal years are converted to whole months)
+	yIndex := strings.Index(duration, "y")
+	if yIndex != -1 {
+		yearStr := duration[:yIndex]
+		var err error
+		years, err = strconv.ParseFloat(yearStr, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid year format")
+		}
+		duration = duration[yIndex+1:]
+	}
+
+	// Find 'm' for months
+	mIndex := strings.Index(duration, "m")
+	if mIndex != -1 {
+		monthStr := duration[:mIndex]
+		var err error
+		months, err = strconv.Atoi(monthStr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid month format")
+		}
+	}
+
+	totalMonths := int(math.Round(years*12)) + months
+	if totalMonths <= 0 {
+		return 0, fmt.Errorf("duration must be greater than 0")
+	}
+
+	return totalMonths, nil
+}
@@ -0,0 +1,122 @@
+package calc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"", 0},
+		{"500", 500},
+		{"500K", 500000},
+		{"-4k", -4000},
+		{"6.5%", 6.5},
+		{"1,250,000", 1250000},
+		{"1,000k", 1000000},
+		{"1,234.5", 1234.5},
+		{"1 250 000", 1250000},
+		{"-1,234.5%", -1234.5},
+		{"2B", 2000000000},
+		{"1.5t", 1500000000000},
+		{"50L", 5000000},
+		{"2.5 lakh", 250000},
+		{"2Cr", 20000000},
+		{"1.2 crore", 12000000},
+		{"1.5LCr", 1500000000000},
+		{"10,00,000", 1000000},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.input)
+		if err != nil {
+			t.Errorf("ParseAmount(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmountInvalid(t *testing.T) {
+	for _, input := range []string{"abc", "1.2.3", "K", "5x"} {
+		if _, err := ParseAmount(input); err == nil {
+			t.Errorf("ParseAmount(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestParseAppreciationRates(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []float64
+	}{
+		{"", []float64{0}},
+		{"4", []float64{4}},
+		{"10,5,3", []float64{10, 5, 3}},
+		{"500K,0K", []float64{500000, 0}},
+		// Unquoted, every comma separates entries
+		{"1,100", []float64{1, 100}},
+		{"0,250,250", []float64{0, 250, 250}},
+		{"1,250,000", []float64{1, 250, 0}},
+		// Quoted, commas are thousands separators
+		{`"1,250,000"`, []float64{1250000}},
+		{`"1,250,000",0`, []float64{1250000, 0}},
+		{`500K, "1,000k"`, []float64{500000, 1000000}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAppreciationRates(tt.input)
+		if err != nil {
+			t.Errorf("ParseAppreciationRates(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAppreciationRates(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseAppreciationRatesInvalid(t *testing.T) {
+	for _, input := range []string{"4,x", `"1,250`, "3,5y"} {
+		if _, err := ParseAppreciationRates(input); err == nil {
+			t.Errorf("ParseAppreciationRates(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"30y", 360},
+		{"2.5y", 30},
+		{"6m", 6},
+		{"5y6m", 66},
+		{"1.5y6m", 24},
+		{"30", 360},
+		{"2.5", 30},
+		{" 30Y ", 360},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	for _, input := range []string{"", "0", "0y", "0m", "0y0m", "-5y", "-6m", "-30", "1y-12m", "abc", "5x"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package calc
+
+import "math"
+
+// RateForYear returns the rate for a 0-based projection year; the last rate applies to all remaining years
+func RateForYear(rates []float64, year int) float64 {
+	if year >= len(rates) {
+		year = len(rates) - 1
+	}
+	return rates[year]
+}
+
+// InflationFactor returns cumulative inflation after the given months, compounding each year's rate
+// Partial years compound at that year's rate
+func InflationFactor(cfg *Inputs, months int) float64 {
+	factor := 1.0
+	for year := 0; year < months/12; year++ {
+		factor *= 1 + RateForYear(cfg.InflationRates, year)/100
+	}
+	if remainingMonths := months % 12; remainingMonths > 0 {
+		factor *= math.Pow(1+RateForYear(cfg.InflationRates, months/12)/100, float64(remainingMonths)/12.0)
+	}
+	return factor
+}
+
+// CostInflationFactor returns the inflation multiplier on recurring costs in the given month (0-based)
+// Costs step once a year; with InflationAtStart each year's rate applies from that year's first month
+func CostInflationFactor(cfg *Inputs, month int) float64 {
+	years := month / 12
+	if cfg.InflationAtStart {
+		years++
+	}
+	return InflationFactor(cfg, years*12)
+}
+
+// MonthlyPayment calculates the monthly payment using the amortization formula
+// M = P * [r(1+r)^n] / [(1+r)^n - 1]
+func MonthlyPayment(principal, monthlyRate float64, months int) float64 {
+	if monthlyRate == 0 {
+		return principal / float64(months)
+	}
+
+	factor := math.Pow(1+monthlyRate, float64(months))
+	monthlyPayment := principal * (monthlyRate * factor) / (factor - 1)
+	return monthlyPayment
+}
+
+// Results holds the month-by-month results projected from Inputs (index 0 is the first month)
+type Results struct {
+	BuyingCosts   []float64
+	RentingCosts  []float64
+	LoanBalance   []float64 // Remaining loan balance after each month's payment
+	PrincipalPaid []float64 // Cumulative principal paid
+	InterestPaid  []float64 // Cumulative interest paid
+
+	BalloonPayment float64 // Balance paid off in the balloon month on top of its regular payment (0 = none)
+
+	// KEEP scenario investment tracking
+	KeepInvestmentValue []float64 // Investment value at each month
+	KeepRealCosts       []float64 // Cumulative real out-of-pocket costs at each month
+	KeepNetPosition     []float64 // Net position (investment - real costs) at each month
+}
+
+// LoanPaymentInMonth returns what was paid toward both loans in the given month (0-based): interest plus principal,
+// including any lump sum or balloon, and nothing once the loans are paid off
+func LoanPaymentInMonth(r *Results, month int) float64 {
+	paid := r.PrincipalPaid[month] + r.InterestPaid[month]
+	if month > 0 {
+		paid -= r.PrincipalPaid[month-1] + r.InterestPaid[month-1]
+	}
+	return paid
+}
+
+// ProjectionMonths returns how many months the monthly arrays cover: the horizon,
+// extended to the loan term when that's longer so the loan fully amortizes
+func ProjectionMonths(cfg *Inputs) int {
+	return max(cfg.HorizonMonths, cfg.TotalMonths, cfg.SecondLoanMonths)
+}
+
+// Project computes the monthly costs for buying and renting, and the loan and KEEP tracking arrays, from cfg
+// It normalizes cfg first, filling in its derived fields
+func Project(cfg *Inputs) *Results {
+	Normalize(cfg)
+	maxMonths := ProjectionMonths(cfg) // Projection horizon, or the loan term if longer
+
+	r := &Results{}
+	r.BuyingCosts = make([]float64, maxMonths)
+	r.RentingCosts = make([]float64, maxMonths)
+	r.LoanBalance = make([]float64, maxMonths)
+	r.PrincipalPaid = make([]float64, maxMonths)
+	r.InterestPaid = make([]float64, maxMonths)
+
+	// Calculate monthly recurring expenses from the inputs
+	totalAnnualExpenses := cfg.AnnualInsurance + cfg.AnnualTaxes
+	monthlyRecurringExpenses := (totalAnnualExpenses / 12) + cfg.MonthlyExpenses + cfg.UtilitiesDiff
+
+	// Calculate current rental cost with annual increases
+	currentRentingCost := cfg.TotalMonthlyRentingCost
+
+	// Track current recurring expenses (will increase with inflation)
+	currentRecurringExpenses := monthlyRecurringExpenses
+
+	// Track remaining loan balance
+	currentBalance := cfg.LoanAmount
+	totalPrincipalPaid := 0.0
+	totalInterestPaid := 0.0
+	secondBalance := cfg.SecondLoanAmount
+	secondMonthlyRate := cfg.SecondLoanRate / 100 / 12
+
+	for i := 0; i < maxMonths; i++ {
+		// Apply the previous year's inflation to all costs at each anniversary (except the first month)
+		// With InflationAtStart, each year's own rate applies from its first month instead
+		if i%12 == 0 && (i > 0 || cfg.InflationAtStart) {
+			inflationYear := i/12 - 1
+			if cfg.InflationAtStart {
+				inflationYear = i / 12
+			}
+			inflation := RateForYear(cfg.InflationRates, inflationYear)
+			currentRentingCost *= (1 + inflation/100)
+			currentRecurringExpenses *= (1 + inflation/100)
+		}
+
+		// Add value-based maintenance; value-based tax & insurance replace their inflated amount
+		recurringExpenses := currentRecurringExpenses + MonthlyMaintenance(cfg, i)
+		if cfg.ValueBasedEscrow {
+			recurringExpenses += MonthlyValueBasedEscrow(cfg, i) - cfg.AnnualInsurance/12*CostInflationFactor(cfg, i)
+		} else if cfg.ReassessYears > 0 {
+			recurringExpenses += MonthlyReassessedTax(cfg, i) - cfg.AnnualInsurance/12*CostInflationFactor(cfg, i)
+		}
+
+		// Set renting cost for this month; a rent step replaces the inflated rent with the stepped one
+		r.RentingCosts[i] = currentRentingCost
+		if RentStepped(cfg) {
+			r.RentingCosts[i] += RentForMonth(cfg, cfg.MonthlyRent, i) - cfg.MonthlyRent*CostInflationFactor(cfg, i)
+		}
+
+		// Buying cost: loan payment stops after loan duration (or early payoff), but recurring expenses continue
+		if i < cfg.TotalMonths && currentBalance > 0 {
+			// Calculate interest for this month
+			interestPayment := currentBalance * cfg.MonthlyRate
+			// Payment is fixed, except the final payment after a prepayment only covers what's owed
+			loanPayment := math.Min(cfg.MonthlyLoanPayment, currentBalance+interestPayment)
+			// Principal payment is the remainder
+			principalPayment := loanPayment - interestPayment
+			// Reduce the balance
+			currentBalance -= principalPayment
+
+			// Apply the lump-sum prepayment after this month's normal principal
+			if cfg.LumpSumAmount > 0 && i == cfg.LumpSumMonth-1 {
+				lumpSum := math.Min(cfg.LumpSumAmount, currentBalance)
+				currentBalance -= lumpSum
+				principalPayment += lumpSum
+				loanPayment += lumpSum
+			}
+
+			// A balloon loan's remaining balance is due with the payment in the balloon month
+			if cfg.BalloonAmortMonths > 0 && i == cfg.TotalMonths-1 {
+				r.BalloonPayment = currentBalance
+				principalPayment += currentBalance
+				loanPayment += currentBalance
+				currentBalance = 0
+			}
+
+			// The buydown subsidy covers part of the payment; principal and interest still follow the note rate
+			r.BuyingCosts[i] = loanPayment - BuydownSubsidy(cfg, i) + recurringExpenses
+
+			// Track cumulative amounts
+			totalPrincipalPaid += principalPayment
+			totalInterestPaid += interestPayment
+
+			// Store remaining balance after this payment
+			r.LoanBalance[i] = currentBalance
+			r.PrincipalPaid[i] = totalPrincipalPaid
+			r.InterestPaid[i] = totalInterestPaid
+		} else {
+			// After loan is paid off, only recurring expenses remain
+			r.BuyingCosts[i] = recurringExpenses
+			r.LoanBalance[i] = 0
+			r.PrincipalPaid[i] = totalPrincipalPaid
+			r.InterestPaid[i] = totalInterestPaid
+		}
+
+		// The second loan amortizes on its own term; its payment and balance add to the first loan's
+		if cfg.SecondLoanAmount > 0 {
+			if i < cfg.SecondLoanMonths && secondBalance > 0 {
+				interestPayment := secondBalance * secondMonthlyRate
+				loanPayment := math.Min(cfg.SecondLoanPayment, secondBalance+interestPayment)
+				secondBalance -= loanPayment - interestPayment
+				r.BuyingCosts[i] += loanPayment
+				totalPrincipalPaid += loanPayment - interestPayment
+				totalInterestPaid += interestPayment
+			}
+			r.LoanBalance[i] += secondBalance
+			r.PrincipalPaid[i] = totalPrincipalPaid
+			r.InterestPaid[i] = totalInterestPaid
+		}
+
+		// One-off expenses land in their month on top of everything else
+		r.BuyingCosts[i] += cfg.OneTimeCosts[i+1] + BuyerMovingCost(cfg, i)
+		r.RentingCosts[i] += RenterMovingCost(cfg, i)
+	}
+
+	// The itemization benefit lowers each month's cost by its share of that year's tax savings, like lower withholding
+	if cfg.MarginalTaxRate > 0 {
+		for i := range r.BuyingCosts {
+			r.BuyingCosts[i] -= ItemizationBenefit(cfg, r, i/12) / float64(min(12, maxMonths-i/12*12))
+		}
+	}
+
+	// Calculate KEEP investment tracking arrays
+	trackKeepInvestment(cfg, r, maxMonths)
+	return r
+}
+
+// trackKeepInvestment populates the projection's investment tracking arrays for KEEP scenario
+func trackKeepInvestment(cfg *Inputs, r *Results, maxMonths int) {
+	r.KeepInvestmentValue = make([]float64, maxMonths)
+	r.KeepRealCosts = make([]float64, maxMonths)
+	r.KeepNetPosition = make([]float64, maxMonths)
+
+	investmentValue := 0.0
+	totalRealCosts := 0.0
+
+	for i := 0; i < maxMonths; i++ {
+		monthlyInvestmentRate := AfterTaxMonthlyReturn(cfg, RateForYear(cfg.InvestmentReturnRates, i/12))
+		monthlyCost := r.BuyingCosts[i] - KeepRentalIncome(cfg, i) + RentalIncomeTax(cfg, r, i)
+
+		if monthlyCost < 0 {
+			// Income: invest it
+			investmentValue += -monthlyCost
+		} else if monthlyCost > 0 {
+			// Expense: first use investment value, then real costs
+			if investmentValue >= monthlyCost {
+				investmentValue -= monthlyCost
+			} else {
+				// Use up all investment, remainder is real cost
+				deficit := monthlyCost - investmentValue
+				investmentValue = 0
+				totalRealCosts += deficit
+			}
+		}
+
+		// Compound whatever investment value remains
+		investmentValue *= (1 + monthlyInvestmentRate)
+
+		// Store values for this month
+		r.KeepInvestmentValue[i] = investmentValue
+		r.KeepRealCosts[i] = totalRealCosts
+		r.KeepNetPosition[i] = investmentValue - totalRealCosts
+	}
+}
+
+// BuydownSubsidy returns the part of the given month's (0-based) loan payment covered by a temporary buydown
+// The borrower pays as if the rate were reduced for that year, on the same loan amount and schedule
+func BuydownSubsidy(cfg *Inputs, month int) float64 {
+	year := month / 12
+	if year >= len(cfg.BuydownRates) || month >= cfg.TotalMonths {
+		return 0
+	}
+	reducedRate := (cfg.AnnualRate - cfg.BuydownRates[year]) / 100 / 12
+	return cfg.MonthlyLoanPayment - MonthlyPayment(cfg.LoanAmount, reducedRate, AmortizationMonths(cfg))
+}
+
+// AmortizationMonths returns the schedule the loan payment is computed over: the full term, even for a balloon loan
+func AmortizationMonths(cfg *Inputs) int {
+	if cfg.BalloonAmortMonths > 0 {
+		return cfg.BalloonAmortMonths
+	}
+	return cfg.TotalMonths
+}
+
+// UpfrontBuyingCosts returns the one-time purchase costs paid on top of the downpayment
+func UpfrontBuyingCosts(cfg *Inputs) float64 {
+	return cfg.ClosingCosts + LoanPointsCost(cfg)
+}
+
+// LoanPointsCost returns the upfront cost of loan points
+func LoanPointsCost(cfg *Inputs) float64 {
+	return cfg.LoanAmount * cfg.LoanPoints / 100
+}
+
+// ItemizationBenefit returns the tax saved in the given year (0-based) by itemizing instead of taking the standard deduction
+// Mortgage interest and property tax only help by the amount they lift itemized deductions above the standard deduction;
+// other itemized deductions that would beat it anyway (renting too) aren't credited to buying. State income tax is
+// deductible either way, but shares the SALT cap with property tax, so it also limits how much property tax counts
+func ItemizationBenefit(cfg *Inputs, r *Results, year int) float64 {
+	start := year * 12
+	end := min(start+12, len(r.InterestPaid))
+	if cfg.MarginalTaxRate <= 0 || start >= end {
+		return 0
+	}
+
+	interest := r.InterestPaid[end-1]
+	if start > 0 {
+		interest -= r.InterestPaid[start-1]
+	}
+	// Annual amounts are inflated and prorated for a partial final year; the SALT cap is prorated but not inflated
+	scale := CostInflationFactor(cfg, start) * float64(end-start) / 12
+	standard := cfg.StandardDeduction * scale
+	years := float64(end-start) / 12
+	stateIncomeTax := cfg.StateIncomeTax * scale
+	other := cfg.OtherItemized * scale
+	itemized := interest + DeductibleSALT(cfg, cfg.PropertyTax*scale+stateIncomeTax, years) + other
+	renterItemized := DeductibleSALT(cfg, stateIncomeTax, years) + other
+	return (math.Max(standard, itemized) - math.Max(standard, renterItemized)) * cfg.MarginalTaxRate / 100
+}
+
+// DeductibleSALT returns the part of state and local taxes (property plus income tax) deductible over years of the SALT cap
+func DeductibleSALT(cfg *Inputs, salt, years float64) float64 {
+	if cfg.SaltCap <= 0 {
+		return salt
+	}
+	return math.Min(salt, cfg.SaltCap*years)
+}
+
+// BuyerMovingCost returns the moving cost buyers pay in the given month (0-based): a single move at purchase
+// Keeping (SELL vs KEEP) means not moving at all
+func BuyerMovingCost(cfg *Inputs, month int) float64 {
+	if month != 0 || cfg.SellVsKeep {
+		return 0
+	}
+	return cfg.MovingCost
+}
+
+// RenterMovingCost returns the inflated moving cost renters pay in the given month (0-based)
+// Renters move at the start and again every RentMoveMonths
+func RenterMovingCost(cfg *Inputs, month int) float64 {
+	if month != 0 && (cfg.RentMoveMonths <= 0 || month%cfg.RentMoveMonths != 0) {
+		return 0
+	}
+	return cfg.MovingCost * CostInflationFactor(cfg, month)
+}
+
+// UpfrontRentingCosts returns move-in costs beyond the deposit: the broker fee plus a prepaid last month
+func UpfrontRentingCosts(cfg *Inputs) float64 {
+	return cfg.RentBrokerFee + PrepaidLastMonthRent(cfg)
+}
+
+// PrepaidLastMonthRent returns the last month's rent paid at move-in (0 unless first & last month is required)
+func PrepaidLastMonthRent(cfg *Inputs) float64 {
+	if cfg.FirstLastMonth {
+		return cfg.MonthlyRent
+	}
+	return 0
+}
+
+// RecoverableRentingCosts returns what the renter gets back at the end: 75% of the deposit and the prepaid last month
+// The broker fee is never recovered
+func RecoverableRentingCosts(cfg *Inputs) float64 {
+	return cfg.RentDeposit*0.75 + PrepaidLastMonthRent(cfg)
+}
+
+// RentStepped reports whether rent follows a lease-renewal step instead of inflation
+func RentStepped(cfg *Inputs) bool {
+	return cfg.RentStep != 0
+}
+
+// RentForMonth returns the monthly rent in the given month (0-based), starting from baseRent
+// Rent inflates annually like other costs unless a rent step applies at each 12-month renewal
+func RentForMonth(cfg *Inputs, baseRent float64, month int) float64 {
+	if !RentStepped(cfg) {
+		return baseRent * CostInflationFactor(cfg, month)
+	}
+	renewals := float64(month / 12)
+	if cfg.RentStepPct {
+		return baseRent * math.Pow(1+cfg.RentStep/100, renewals)
+	}
+	return baseRent + cfg.RentStep*renewals
+}
+
+// KeepRentalIncome returns the effective rental income received in the given month if keeping (0 when not renting out)
+// Rent is inflated annually like other recurring amounts, then reduced by vacancy and the management fee
+func KeepRentalIncome(cfg *Inputs, month int) float64 {
+	if !cfg.IncludeRentalIncome {
+		return 0
+	}
+	return EffectiveMonthlyRentalIncome(cfg) * CostInflationFactor(cfg, month)
+}
+
+// Residential rental property depreciates straight-line over 27.5 years and depreciation is recaptured at 25% on sale
+const (
+	DepreciationMonths        = 330
+	DepreciationRecaptureRate = 25
+)
+
+// MonthlyDepreciation returns the depreciation deducted in the given month (0-based) while renting the asset out
+func MonthlyDepreciation(cfg *Inputs, month int) float64 {
+	if !cfg.IncludeRentalIncome || month >= DepreciationMonths {
+		return 0
+	}
+	return cfg.DepreciableBasis / DepreciationMonths
+}
+
+// AccumulatedDepreciation returns the depreciation taken over the given number of months of renting the asset out
+func AccumulatedDepreciation(cfg *Inputs, months int) float64 {
+	if !cfg.IncludeRentalIncome {
+		return 0
+	}
+	return cfg.DepreciableBasis / DepreciationMonths * float64(min(months, DepreciationMonths))
+}
+
+// RentalIncomeTax returns the income tax on the given month's rental income after deductible costs and depreciation
+// Everything but loan principal is deductible; a loss saves no tax and isn't carried forward
+func RentalIncomeTax(cfg *Inputs, r *Results, month int) float64 {
+	if !cfg.IncludeRentalIncome || cfg.RentalIncomeTaxRate <= 0 {
+		return 0
+	}
+	principal := r.PrincipalPaid[month]
+	if month > 0 {
+		principal -= r.PrincipalPaid[month-1]
+	}
+	taxableIncome := KeepRentalIncome(cfg, month) - (r.BuyingCosts[month] - principal) - MonthlyDepreciation(cfg, month)
+	return math.Max(0, taxableIncome) * cfg.RentalIncomeTaxRate / 100
+}
+
+// EffectiveMonthlyRentalIncome returns today's monthly rent after vacancy losses and the management fee on collected rent
+func EffectiveMonthlyRentalIncome(cfg *Inputs) float64 {
+	collected := cfg.MonthlyRentalIncome * (1 - cfg.VacancyRate/100)
+	return collected * (1 - cfg.ManagementFeePct/100)
+}
+
+// AppreciatedValue compounds startingValue by the year-by-year appreciation rates over the given months
+// With MonthlyAppreciation each month compounds by 1/12 of its year's nominal rate instead, like investment returns,
+// so a year at 12% grows the value by 12.68% rather than 12%
+func AppreciatedValue(cfg *Inputs, startingValue float64, months int) float64 {
+	value := startingValue
+	if cfg.MonthlyAppreciation {
+		for month := 0; month < months; month++ {
+			value *= 1 + RateForYear(cfg.AppreciationRates, month/12)/100/12
+		}
+		return value
+	}
+	for year := 0; year < months/12; year++ {
+		value *= 1 + RateForYear(cfg.AppreciationRates, year)/100
+	}
+	if remainingMonths := months % 12; remainingMonths > 0 {
+		value *= math.Pow(1+RateForYear(cfg.AppreciationRates, months/12)/100, float64(remainingMonths)/12.0)
+	}
+	return value
+}
+
+// StartingAssetValue returns the asset value projections start from: current market value (SELL vs KEEP) or purchase price (BUY vs RENT)
+func StartingAssetValue(cfg *Inputs) float64 {
+	if cfg.SellVsKeep {
+		return cfg.CurrentMarketValue
+	}
+	return cfg.PurchasePrice
+}
+
+// MonthlyMaintenance returns maintenance for the given month as a percent of the asset value at the start of that year
+func MonthlyMaintenance(cfg *Inputs, month int) float64 {
+	if cfg.MaintenancePct <= 0 {
+		return 0
+	}
+	return AppreciatedValue(cfg, StartingAssetValue(cfg), month/12*12) * cfg.MaintenancePct / 100 / 12
+}
+
+// MonthlyValueBasedEscrow returns tax & insurance for the given month when they scale with the asset value
+// The first-year input implies a rate on the starting value, which is applied to the value at the start of each year
+func MonthlyValueBasedEscrow(cfg *Inputs, month int) float64 {
+	impliedRate := cfg.AnnualInsurance / StartingAssetValue(cfg)
+	return AppreciatedValue(cfg, StartingAssetValue(cfg), month/12*12) * impliedRate / 12
+}
+
+// MonthlyReassessedTax returns tax & insurance for the given month under periodic reassessment
+// Every ReassessYears the basis resets to the asset value at the first-year implied rate; in between it grows
+// with inflation, capped at ReassessCapPct per year
+func MonthlyReassessedTax(cfg *Inputs, month int) float64 {
+	year := month / 12
+	lastReassessment := year / cfg.ReassessYears * cfg.ReassessYears
+	annual := AppreciatedValue(cfg, StartingAssetValue(cfg), lastReassessment*12) * cfg.AnnualInsurance / StartingAssetValue(cfg)
+	for y := lastReassessment; y < year; y++ {
+		annual *= 1 + math.Min(RateForYear(cfg.InflationRates, y), cfg.ReassessCapPct)/100
+	}
+	return annual / 12
+}
+
+// AfterTaxMonthlyReturn converts an annual return (%) into a monthly growth rate after the investment tax drag
+func AfterTaxMonthlyReturn(cfg *Inputs, annualReturn float64) float64 {
+	return annualReturn * (1 - cfg.InvestmentTaxRate/100) / 100 / 12
+}
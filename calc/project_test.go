@@ -0,0 +1,237 @@
+package calc
+
+import (
+	"math"
+	"testing"
+)
+
+// buyVsRentInputs returns a BUY vs RENT scenario: a 1.32M home with 20% down on a 30-year loan at 5.5%
+func buyVsRentInputs() Inputs {
+	return Inputs{
+		InflationRate:         3,
+		InflationRates:        []float64{3},
+		AppreciationRates:     []float64{4},
+		InvestmentReturnRate:  10,
+		InvestmentReturnRates: []float64{10},
+		TaxFreeLimits:         []float64{500000},
+		HorizonMonths:         360,
+		PurchasePrice:         1320000,
+		LoanAmount:            1056000,
+		AnnualRate:            5.5,
+		LoanMonths:            360,
+		AnnualInsurance:       19000,
+		AnnualTaxes:           10000,
+		RentDeposit:           10000,
+		MonthlyRent:           5000,
+		AnnualRentCosts:       3000,
+		IncludeSelling:        true,
+		AgentCommission:       6,
+		StagingCosts:          15000,
+		CapitalGainsTax:       33,
+		ShortTermRate:         33,
+		PrimaryResidence:      true,
+	}
+}
+
+// sellVsKeepInputs returns a SELL vs KEEP scenario: a home bought for 1.32M, worth 2M today, 25 years left on its loan
+func sellVsKeepInputs() Inputs {
+	cfg := buyVsRentInputs()
+	cfg.SellVsKeep = true
+	cfg.CurrentMarketValue = 2000000
+	cfg.OriginalLoanAmount = 1056000
+	cfg.RemainingLoanMonths = 300
+	cfg.MonthsOccupied = ExclusionWindowMonths
+	return cfg
+}
+
+// approxEqual reports whether got is within a cent of want
+func approxEqual(got, want float64) bool {
+	return math.Abs(got-want) < 0.01
+}
+
+func TestNormalize(t *testing.T) {
+	cfg := buyVsRentInputs()
+	Normalize(&cfg)
+	if cfg.TotalMonths != 360 || cfg.Downpayment != 264000 {
+		t.Errorf("Normalize: TotalMonths = %d, Downpayment = %v, want 360 and 264000", cfg.TotalMonths, cfg.Downpayment)
+	}
+	if want := MonthlyPayment(1056000, 5.5/100/12, 360); cfg.MonthlyLoanPayment != want {
+		t.Errorf("Normalize: MonthlyLoanPayment = %v, want %v", cfg.MonthlyLoanPayment, want)
+	}
+
+	// Normalizing again leaves the derived fields unchanged
+	svk := sellVsKeepInputs()
+	Normalize(&svk)
+	balance, payment := svk.LoanAmount, svk.MonthlyLoanPayment
+	Normalize(&svk)
+	if svk.LoanAmount != balance || svk.MonthlyLoanPayment != payment {
+		t.Errorf("Normalize is not repeatable: balance %v then %v", balance, svk.LoanAmount)
+	}
+	if svk.TotalMonths != 300 || !approxEqual(svk.Downpayment, svk.CurrentMarketValue-svk.LoanAmount) {
+		t.Errorf("Normalize: TotalMonths = %d, Downpayment = %v for SELL vs KEEP", svk.TotalMonths, svk.Downpayment)
+	}
+}
+
+func TestProjectLoanSchedule(t *testing.T) {
+	cfg := buyVsRentInputs()
+	r := Project(&cfg)
+	if len(r.BuyingCosts) != 360 {
+		t.Fatalf("Project: %d months, want 360", len(r.BuyingCosts))
+	}
+	if last := r.LoanBalance[359]; math.Abs(last) > 0.01 {
+		t.Errorf("Project: loan balance after the last payment = %v, want 0", last)
+	}
+	for _, month := range []int{0, 59, 119, 359} {
+		if paid := r.PrincipalPaid[month] + r.LoanBalance[month]; !approxEqual(paid, cfg.LoanAmount) {
+			t.Errorf("Project: principal paid + balance at month %d = %v, want %v", month+1, paid, cfg.LoanAmount)
+		}
+	}
+}
+
+// TestProjectNetWorth pins net worth for fixed scenarios so changes to the math show up as diffs
+// The 10y BUY vs RENT case matches the CLI's "RENTING wins by 329.1K" for the same inputs
+func TestProjectNetWorth(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputs     func() Inputs
+		months     int
+		buyCost    float64 // First month's buying cost
+		loanLeft   float64 // Loan balance after months
+		buyNW      float64 // Buying net worth (BUY vs RENT) or KEEP net worth (SELL vs KEEP)
+		rentNW     float64 // Renting net worth (BUY vs RENT) or SELL net worth (SELL vs KEEP)
+		sellVsKeep bool
+	}{
+		{"buy vs rent 5y", buyVsRentInputs, 60, 8412.5185, 976383.9729, 518238.9484, 660042.3544, false},
+		{"buy vs rent 10y", buyVsRentInputs, 120, 8412.5185, 871632.8657, 949497.4972, 1278571.3403, false},
+		{"buy vs rent 30y", buyVsRentInputs, 360, 8412.5185, 0, 3286903.1124, 10347241.8996, false},
+		{"all cash 10y", func() Inputs {
+			cfg := buyVsRentInputs()
+			cfg.LoanAmount = 0
+			return cfg
+		}, 120, 2416.6667, 0, 1821130.3628, 2898751.8402, false},
+		{"sell vs keep 10y", sellVsKeepInputs, 120, 8412.5185, 733811.4388, 669299.5400, 2365320.8887, true},
+	}
+	for _, tt := range tests {
+		cfg := tt.inputs()
+		r := Project(&cfg)
+		var buyNW, rentNW float64
+		if tt.sellVsKeep {
+			buyNW = KeepNetWorth(&cfg, r, tt.months)
+			rentNW = SellNetWorth(&cfg, r, tt.months)
+		} else {
+			_, _, buyNW = NetWorth(&cfg, r, tt.months)
+			rentNW = RentingNetWorth(&cfg, r, tt.months, cfg.MonthlyRent, cfg.InvestmentReturnRates)
+		}
+		checks := []struct {
+			label     string
+			got, want float64
+		}{
+			{"first month's buying cost", r.BuyingCosts[0], tt.buyCost},
+			{"loan balance", r.LoanBalance[tt.months-1], tt.loanLeft},
+			{"buying/keep net worth", buyNW, tt.buyNW},
+			{"renting/sell net worth", rentNW, tt.rentNW},
+		}
+		for _, check := range checks {
+			if !approxEqual(check.got, check.want) {
+				t.Errorf("%s: %s = %.4f, want %.4f", tt.name, check.label, check.got, check.want)
+			}
+		}
+	}
+}
+
+func TestAppreciatedValue(t *testing.T) {
+	tests := []struct {
+		monthly bool
+		months  int
+		want    float64
+	}{
+		{false, 6, 1000000 * math.Sqrt(1.12)},
+		{false, 12, 1120000},
+		{false, 24, 1120000 * 1.06},
+		{true, 6, 1000000 * math.Pow(1.01, 6)},
+		{true, 12, 1000000 * math.Pow(1.01, 12)},
+		{true, 24, 1000000 * math.Pow(1.01, 12) * math.Pow(1.005, 12)},
+	}
+	for _, tt := range tests {
+		cfg := Inputs{AppreciationRates: []float64{12, 6}, MonthlyAppreciation: tt.monthly}
+		if got := AppreciatedValue(&cfg, 1000000, tt.months); !approxEqual(got, tt.want) {
+			t.Errorf("AppreciatedValue(monthly=%v, %d months) = %.2f, want %.2f", tt.monthly, tt.months, got, tt.want)
+		}
+	}
+}
+
+// TestItemizationBenefit checks that state income tax shares the SALT cap with property tax
+func TestItemizationBenefit(t *testing.T) {
+	r := &Results{InterestPaid: make([]float64, 12)}
+	r.InterestPaid[11] = 25000
+	tests := []struct {
+		name           string
+		stateIncomeTax float64
+		saltCap        float64
+		want           float64
+	}{
+		{"property tax only", 0, 10000, (25000 + 8000 - 30000) * 0.3},
+		{"state income tax fills the cap", 6000, 10000, (25000 + 10000 - 30000) * 0.3},
+		{"no cap", 6000, 0, (25000 + 14000 - 30000) * 0.3},
+	}
+	for _, tt := range tests {
+		cfg := Inputs{
+			InflationRates:    []float64{0},
+			MarginalTaxRate:   30,
+			StandardDeduction: 30000,
+			PropertyTax:       8000,
+			StateIncomeTax:    tt.stateIncomeTax,
+			SaltCap:           tt.saltCap,
+		}
+		if got := ItemizationBenefit(&cfg, r, 0); !approxEqual(got, tt.want) {
+			t.Errorf("%s: ItemizationBenefit = %.2f, want %.2f", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestProjectBalloon checks that the balloon balance is paid with the balloon month's payment
+func TestProjectBalloon(t *testing.T) {
+	amortizing := buyVsRentInputs()
+	full := Project(&amortizing)
+
+	cfg := buyVsRentInputs()
+	cfg.BalloonMonths = 84
+	r := Project(&cfg)
+
+	if !approxEqual(r.BalloonPayment, full.LoanBalance[83]) {
+		t.Errorf("BalloonPayment = %.2f, want the 7-year balance of %.2f", r.BalloonPayment, full.LoanBalance[83])
+	}
+	if r.LoanBalance[83] != 0 || !approxEqual(r.PrincipalPaid[83], cfg.LoanAmount) {
+		t.Errorf("after the balloon month: balance = %.2f, principal paid = %.2f, want 0 and %.2f", r.LoanBalance[83], r.PrincipalPaid[83], cfg.LoanAmount)
+	}
+	if got := r.BuyingCosts[83] - r.BuyingCosts[82]; !approxEqual(got, r.BalloonPayment) {
+		t.Errorf("balloon month costs %.2f more than the month before, want %.2f", got, r.BalloonPayment)
+	}
+	if !approxEqual(r.BuyingCosts[84], full.BuyingCosts[84]-cfg.MonthlyLoanPayment) {
+		t.Errorf("month after the balloon costs %.2f, want no loan payment", r.BuyingCosts[84])
+	}
+}
+
+func TestLoanPaymentInMonth(t *testing.T) {
+	cfg := buyVsRentInputs()
+	cfg.SecondLoanAmount = 100000
+	cfg.SecondLoanRate = 8
+	cfg.SecondLoanMonths = 60
+	cfg.LumpSumAmount = 50000
+	cfg.LumpSumMonth = 24
+	r := Project(&cfg)
+
+	tests := []struct {
+		month int
+		want  float64
+	}{
+		{0, cfg.MonthlyLoanPayment + cfg.SecondLoanPayment},
+		{23, cfg.MonthlyLoanPayment + cfg.SecondLoanPayment + 50000},
+		{60, cfg.MonthlyLoanPayment},
+	}
+	for _, tt := range tests {
+		if got := LoanPaymentInMonth(r, tt.month); !approxEqual(got, tt.want) {
+			t.Errorf("LoanPaymentInMonth(%d) = %.2f, want %.2f", tt.month, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	chartHeight       = 15
+	chartDefaultWidth = 80
+	chartLabelWidth   = 9
+)
+
+// chartSeries is a named line in an ASCII chart, sampled by month
+type chartSeries struct {
+	name   string
+	marker rune
+	value  func(months int) float64
+}
+
+// terminalWidth returns the width of stdout, or chartDefaultWidth if it can't be detected
+func terminalWidth() int {
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width <= 0 {
+		return chartDefaultWidth
+	}
+	return width
+}
+
+// displayNetWorthChart renders an ASCII line chart of the given series over the full projection horizon
+func displayNetWorthChart(title string, series []chartSeries) {
+	horizon := len(monthly.BuyingCosts)
+	plotWidth := terminalWidth() - chartLabelWidth - 4
+	if plotWidth < 20 {
+		plotWidth = 20
+	}
+
+	// Sample each series at evenly spaced months
+	values := make([][]float64, len(series))
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for s, line := range series {
+		values[s] = make([]float64, plotWidth)
+		for col := 0; col < plotWidth; col++ {
+			months := (col + 1) * horizon / plotWidth
+			if months < 1 {
+				months = 1
+			}
+			v := line.value(months)
+			values[s][col] = v
+			minValue = math.Min(minValue, v)
+			maxValue = math.Max(maxValue, v)
+		}
+	}
+	if maxValue == minValue {
+		maxValue = minValue + 1
+	}
+
+	// Plot markers on a grid (row 0 is the top)
+	grid := make([][]rune, chartHeight)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", plotWidth))
+	}
+	for s, line := range series {
+		for col, v := range values[s] {
+			row := chartHeight - 1 - int(math.Round((v-minValue)/(maxValue-minValue)*float64(chartHeight-1)))
+			if grid[row][col] != ' ' && grid[row][col] != line.marker {
+				grid[row][col] = '#'
+			} else {
+				grid[row][col] = line.marker
+			}
+		}
+	}
+
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
+	noteStyle := re.NewStyle().Italic(true).Foreground(MonokaiGrey).PaddingLeft(2)
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render(title))
+
+	// Y-axis labels at top, middle, and bottom rows
+	for row := 0; row < chartHeight; row++ {
+		label := ""
+		switch row {
+		case 0:
+			label = formatCurrency(maxValue)
+		case chartHeight / 2:
+			label = formatCurrency((maxValue + minValue) / 2)
+		case chartHeight - 1:
+			label = formatCurrency(minValue)
+		}
+		fmt.Printf("%*s │%s\n", chartLabelWidth, label, string(grid[row]))
+	}
+
+	// X-axis with year markers at start, middle, and end
+	fmt.Printf("%*s └%s\n", chartLabelWidth, "", strings.Repeat("─", plotWidth))
+	midLabel := fmt.Sprintf("%dy", horizon/24)
+	endLabel := fmt.Sprintf("%dy", horizon/12)
+	axis := []rune(strings.Repeat(" ", plotWidth))
+	copy(axis, []rune("0y"))
+	copy(axis[plotWidth/2-len(midLabel)/2:], []rune(midLabel))
+	copy(axis[plotWidth-len(endLabel):], []rune(endLabel))
+	fmt.Printf("%*s  %s\n", chartLabelWidth, "", string(axis))
+
+	// Legend
+	legend := make([]string, len(series))
+	for s, line := range series {
+		legend[s] = fmt.Sprintf("%c %s", line.marker, line.name)
+	}
+	fmt.Println(noteStyle.Render(strings.Join(legend, "   ") + "   # overlap"))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// copyToClipboard is set by the --clipboard flag
+var copyToClipboard bool
+
+// clipboardText is the plain-text comparison table recorded for --clipboard
+var clipboardText string
+
+// recordClipboardTable keeps an unstyled rendering of the comparison table for --clipboard
+func recordClipboardTable(title string, rows [][]string, notes string) {
+	if !copyToClipboard {
+		return
+	}
+	rows, _ = dropZeroColumns(rows)
+
+	t := applyTableBorder(table.New()).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := lipgloss.NewStyle().Padding(0, 1)
+			if col > 0 {
+				style = style.Align(lipgloss.Right)
+			}
+			return style
+		})
+
+	clipboardText = title + "\n" + ansi.Strip(t.String()) + "\n"
+	if notes != "" {
+		clipboardText += "\n" + notes + "\n"
+	}
+}
+
+// copyComparisonToClipboard copies the recorded comparison table to the OS clipboard
+// Headless systems without a clipboard get a warning instead of an error
+func copyComparisonToClipboard() {
+	if clipboardText == "" {
+		return
+	}
+	if clipboard.Unsupported {
+		fmt.Println("Warning: no clipboard is available on this system; skipping --clipboard")
+		return
+	}
+	if err := clipboard.WriteAll(clipboardText); err != nil {
+		fmt.Println("Warning: could not copy to the clipboard:", err)
+		return
+	}
+	fmt.Println("Copied the comparison table to the clipboard")
+}
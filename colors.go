@@ -1,6 +1,13 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+)
 
 // Monokai Pro color scheme
 var (
@@ -8,6 +15,8 @@ var (
 	MonokaiOrange = lipgloss.Color("#FC9867") // Secondary accent - group headers
 	MonokaiCyan   = lipgloss.Color("81")      // Tertiary accent - labels, table headers
 	MonokaiBorder = lipgloss.Color("238")     // Borders
+	MonokaiRed    = lipgloss.Color("203")     // Errors - validation messages
+	MonokaiGreen  = lipgloss.Color("#A9DC76") // Positive outcomes - rent/sell wins in grids
 
 	// Adaptive colors for different terminal backgrounds
 	MonokaiAdaptiveText = lipgloss.AdaptiveColor{
@@ -19,3 +28,130 @@ var (
 		Dark:  "250", // Light gray for dark backgrounds
 	}
 )
+
+// Theme is a set of colors used for all styled output
+type Theme struct {
+	Pink, Orange, Cyan, Border, Red, Green lipgloss.Color
+	Text, Grey                             lipgloss.AdaptiveColor
+}
+
+// Available themes for --theme
+var themes = map[string]Theme{
+	// Monokai Pro, tuned for dark terminals (the original palette)
+	"dark": {
+		Pink: "#FF6188", Orange: "#FC9867", Cyan: "81", Border: "238", Red: "203", Green: "#A9DC76",
+		Text: lipgloss.AdaptiveColor{Light: "240", Dark: "255"},
+		Grey: lipgloss.AdaptiveColor{Light: "243", Dark: "250"},
+	},
+	// Deeper variants of the same hues that stay readable on light backgrounds
+	"light": {
+		Pink: "#C2185B", Orange: "#D84315", Cyan: "25", Border: "250", Red: "160", Green: "28",
+		Text: lipgloss.AdaptiveColor{Light: "236", Dark: "236"},
+		Grey: lipgloss.AdaptiveColor{Light: "242", Dark: "242"},
+	},
+}
+
+// themeName is set by the --theme flag ("auto" detects the terminal background)
+var themeName = "dark"
+
+// validThemeName reports whether name is a known --theme value
+func validThemeName(name string) bool {
+	_, ok := themes[name]
+	return ok || name == "auto"
+}
+
+// applyTheme switches the Monokai color variables to the --theme palette
+func applyTheme() {
+	name := themeName
+	if name == "auto" {
+		name = "dark"
+		if stdoutIsTerminal() && !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+	theme := themes[name]
+	MonokaiPink, MonokaiOrange, MonokaiCyan = theme.Pink, theme.Orange, theme.Cyan
+	MonokaiBorder, MonokaiRed, MonokaiGreen = theme.Border, theme.Red, theme.Green
+	MonokaiAdaptiveText, MonokaiGrey = theme.Text, theme.Grey
+}
+
+// noColor is set by the --no-color flag
+var noColor bool
+
+// plainOutput is set by the --plain flag
+var plainOutput bool
+
+// markdownOutput is set by the --md flag
+var markdownOutput bool
+
+// colorDisabled reports whether styling should be suppressed (--no-color, --plain, --md or NO_COLOR)
+func colorDisabled() bool {
+	return noColor || plainOutput || markdownOutput || os.Getenv("NO_COLOR") != ""
+}
+
+// plainTables reports whether tables should be printed as tab-separated rows
+// (--plain, or stdout piped to a file or another program)
+func plainTables() bool {
+	return plainOutput || !stdoutIsTerminal()
+}
+
+// stdoutIsTerminal reports whether stdout is attached to a terminal
+func stdoutIsTerminal() bool {
+	return term.IsTerminal(os.Stdout.Fd())
+}
+
+// applyColorSettings disables colors on the default renderer (used by the form)
+// when colors are turned off or stdout isn't a terminal
+func applyColorSettings() {
+	if colorDisabled() || !stdoutIsTerminal() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// newRenderer returns a stdout renderer that honors --no-color and NO_COLOR
+func newRenderer() *lipgloss.Renderer {
+	re := lipgloss.NewRenderer(os.Stdout)
+	if colorDisabled() {
+		re.SetColorProfile(termenv.Ascii)
+	}
+	return re
+}
+
+// borderStyle is the --border table border style ("" = normal, or plain ASCII when colors are disabled)
+var borderStyle string
+
+// tableBorders maps --border values to lipgloss borders
+var tableBorders = map[string]lipgloss.Border{
+	"normal":  lipgloss.NormalBorder(),
+	"rounded": lipgloss.RoundedBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"ascii":   lipgloss.ASCIIBorder(),
+	"none":    lipgloss.HiddenBorder(),
+}
+
+// validBorderStyle reports whether style is a known --border value
+func validBorderStyle(style string) bool {
+	_, ok := tableBorders[style]
+	return style == "" || ok
+}
+
+// tableBorder returns the border used for tables: the --border style, else plain ASCII when colors are disabled
+func tableBorder() lipgloss.Border {
+	if border, ok := tableBorders[borderStyle]; ok {
+		return border
+	}
+	if colorDisabled() {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.NormalBorder()
+}
+
+// applyTableBorder sets the table border, dropping the outer edges for --border none
+func applyTableBorder(t *table.Table) *table.Table {
+	t = t.Border(tableBorder())
+	if borderStyle == "none" {
+		t = t.BorderTop(false).BorderBottom(false).BorderLeft(false).BorderRight(false).BorderHeader(false)
+	}
+	return t
+}
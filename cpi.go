@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// cpiSeriesURL is FRED's public CSV download for CPI-U (all urban consumers, seasonally adjusted); no API key needed
+const cpiSeriesURL = "https://fred.stlouisfed.org/graph/fredgraph.csv?id=CPIAUCSL"
+
+// InflationData stores the trailing 12-month CPI inflation rate and the period it covers
+type InflationData struct {
+	Rate      float64   // Year-over-year change in CPI %
+	StartDate time.Time // CPI observation a year before EndDate
+	EndDate   time.Time // Latest CPI observation
+}
+
+// cpiInflation is fetched when --cpi is set (nil otherwise, or when the fetch fails)
+var cpiInflation *InflationData
+
+// fetchCPIInflation fetches the CPIAUCSL series from FRED and computes trailing 12-month inflation
+func fetchCPIInflation() (*InflationData, error) {
+	req, err := http.NewRequest("GET", cpiSeriesURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("FRED returned status %d", resp.StatusCode)
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %v", err)
+	}
+
+	// Rows are "YYYY-MM-DD,value" after a header; missing values are "."
+	cpi := make(map[string]float64)
+	var latest time.Time
+	for _, record := range records[min(1, len(records)):] {
+		if len(record) < 2 {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			continue
+		}
+		cpi[record[0]] = value
+		if date.After(latest) {
+			latest = date
+		}
+	}
+	if latest.IsZero() {
+		return nil, fmt.Errorf("no CPI data returned")
+	}
+
+	yearAgo := latest.AddDate(-1, 0, 0)
+	previous, ok := cpi[yearAgo.Format("2006-01-02")]
+	if !ok || previous == 0 {
+		return nil, fmt.Errorf("no CPI observation for %s", yearAgo.Format("Jan 2006"))
+	}
+
+	return &InflationData{
+		Rate:      (cpi[latest.Format("2006-01-02")]/previous - 1) * 100,
+		StartDate: yearAgo,
+		EndDate:   latest,
+	}, nil
+}
+
+// cpiFootnote describes the CPI inflation rate and the period it was measured over
+func cpiFootnote(data *InflationData) string {
+	return fmt.Sprintf("CPI inflation (FRED CPIAUCSL, %s to %s): %.1f%%",
+		data.StartDate.Format("Jan 2006"), data.EndDate.Format("Jan 2006"), data.Rate)
+}
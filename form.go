@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"calculator/calc"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,6 +20,8 @@ type FormField struct {
 	Required bool
 	IsToggle bool
 	Toggled  bool
+	Edited   bool               // Set once the user changes the text, so blank required fields aren't flagged on first render
+	Validate func(string) error // Validates non-empty text input; nil for toggles
 }
 
 // DialogMode represents the current dialog state
@@ -31,17 +35,18 @@ const (
 
 // FormModel is the bubbletea model for the interactive form
 type FormModel struct {
-	fieldsMap     map[string]*FormField  // All unique fields by key
-	fields        []*FormField           // Flattened array for navigation (points to fieldsMap entries)
-	groups        []FieldGroup
-	currentField  int
-	submitted     bool
-	values        map[string]string
-	err           error
-	marketData    *MarketData
-	dialogMode    DialogMode
-	dialogInput   textinput.Model
-	profileList   []string
+	fieldsMap       map[string]*FormField // All unique fields by key
+	fields          []*FormField          // Flattened array for navigation (points to fieldsMap entries)
+	groups          []FieldGroup
+	currentField    int
+	submitted       bool
+	submitTried     bool // Set by the first Ctrl+K, after which every missing field is flagged
+	values          map[string]string
+	err             error
+	marketData      *MarketData
+	dialogMode      DialogMode
+	dialogInput     textinput.Model
+	profileList     []string
 	selectedProfile int
 }
 
@@ -52,6 +57,7 @@ var (
 	helpStyle    = lipgloss.NewStyle().Foreground(MonokaiGrey)
 	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(MonokaiPink)
 	groupStyle   = lipgloss.NewStyle().Bold(true).Foreground(MonokaiOrange)
+	errorStyle   = lipgloss.NewStyle().Foreground(MonokaiRed)
 )
 
 // FieldGroup represents a group of related fields
@@ -85,8 +91,10 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Name:     "ECONOMIC ASSUMPTIONS",
 			Scenario: "both",
 			Fields: []FormField{
-				makeField("inflation_rate", "Inflation Rate (%)", "Annual inflation for all recurring costs", defaults),
-				makeField("investment_return_rate", "Investment Return Rate (%)", "Expected return on investments. Market averages shown below", defaults),
+				makeField("inflation_rate", "Inflation Rate (%)", "Annual inflation for all recurring costs. Comma-separated values apply to first years, last value for all remaining years (e.g., '6,4,3')", defaults),
+				makeField("investment_return_rate", "Investment Return Rate (%)", "Expected return on investments. Comma-separated values vary by year (e.g., '8,7,6,5'). Market averages shown below", defaults),
+				makeOptionalField("investment_tax_rate", "Investment Tax Rate (%)", "Optional tax on investment gains and dividends, reducing the return each month (e.g., 15)", defaults),
+				makeOptionalField("discount_rate", "Discount Rate (%)", "Optional annual rate for discounting cash flows to present value. Adds an NPV column to the BUY vs RENT comparison", defaults),
 				makeToggleField("include_30year", "Include 30-Year Projections", "Toggle to show 15y, 20y, 30y periods (default: 10y max)", defaults),
 			},
 		},
@@ -95,12 +103,34 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Scenario: "buy_vs_rent",
 			Fields: []FormField{
 				makeField("purchase_price", "Asset Purchase Price ($)", "Initial purchase price of the asset", defaults),
+				makeOptionalField("downpayment_pct", "Downpayment (%)", "Optional percentage down (e.g., 20). When set, the loan amount is computed from the purchase price and Loan Amount is ignored", defaults),
 				makeField("loan_amount", "Loan Amount ($)", "Total mortgage/loan amount", defaults),
 				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate (e.g., 6.5)", defaults),
+				makeOptionalField("buydown", "Rate Buydown (%)", "Optional temporary buydown paid by the builder or seller: rate reductions by year, e.g., '2,1' for a 2-1 buydown", defaults),
+				makeOptionalField("loan_points", "Loan Points (%)", "Optional points / origination fee as a percentage of the loan (e.g., 1.5). Enter the loan rate after any buy-down", defaults),
 				makeField("loan_term", "Loan Term", "Loan duration (e.g., 5y, 30y)", defaults),
+				makeOptionalField("balloon_term", "Balloon Term", "Optional balloon date (e.g., 7y). Payments follow the loan term's schedule and the remaining balance is due at this date", defaults),
+				makeOptionalField("second_loan_amount", "Second Loan Amount ($)", "Optional second mortgage or HELOC, amortized on its own schedule. Reduces the downpayment", defaults),
+				makeOptionalField("second_loan_rate", "Second Loan Rate (%)", "Annual interest rate on the second loan (e.g., 8.5)", defaults),
+				makeOptionalField("second_loan_term", "Second Loan Term", "Second loan duration (e.g., 10y, 15y)", defaults),
+				makeOptionalField("closing_costs", "Closing Costs ($)", "Optional one-time purchase costs beyond the downpayment (title, escrow, inspection)", defaults),
+				makeOptionalField("lump_sum_amount", "Lump-Sum Prepayment ($)", "Optional one-time extra payment toward principal. Monthly payment stays fixed, so the loan finishes early", defaults),
+				makeOptionalField("lump_sum_month", "Lump-Sum Month", "Month number in which the lump sum is paid (e.g., 24)", defaults),
 				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly insurance cost", defaults),
 				makeField("annual_taxes", "Other Annual Costs ($)", "Maintenance costs, etc.", defaults),
 				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly expenses. Typically include utilities, HOA, etc. Can be negative if earning income, e.g., -4K.", defaults),
+				makeOptionalField("utilities_diff", "Utilities Difference ($)", "Optional extra monthly utilities when owning vs renting (inflated). Can be negative if owning costs less", defaults),
+				makeOptionalField("one_time_costs", "One-Time Costs", "Optional one-off expenses as month:amount pairs, e.g., '36:15K,120:8K' for a roof in month 36 and HVAC in month 120. Quote amounts with thousands separators, e.g., '36:\"15,000\"'", defaults),
+				makeToggleField("value_based_escrow", "Value-Based Tax & Insurance", "Toggle to grow tax & insurance with the asset value instead of inflation", defaults),
+				makeOptionalField("reassess_every_years", "Reassess Tax Every (years)", "Optional interval at which tax & insurance reset to the asset value (e.g., 5). Blank means inflation only", defaults),
+				makeOptionalField("reassess_cap", "Reassessment Cap (%)", "Maximum yearly growth of tax & insurance between reassessments. Blank means 2%", defaults),
+				makeOptionalField("maintenance_pct", "Maintenance (% of value)", "Optional annual maintenance as a percent of the home's value (e.g., 1 for the 1% rule). Grows with appreciation", defaults),
+				makeOptionalField("marginal_tax_rate", "Marginal Tax Rate (%)", "Optional income tax rate for the mortgage interest and property tax deduction. Blank means no deduction", defaults),
+				makeOptionalField("property_tax", "Property Tax ($)", "Annual property tax included in tax & insurance, deductible when itemizing", defaults),
+				makeOptionalField("state_income_tax", "State Income Tax ($)", "Annual state and local income tax, deductible when itemizing whether you buy or rent. Shares the SALT cap with property tax. Inflated", defaults),
+				makeOptionalField("salt_cap", "SALT Cap ($)", "Annual limit on deductible property tax plus state income tax (not inflated). Blank means 10K; 0 means no cap", defaults),
+				makeOptionalField("standard_deduction", "Standard Deduction ($)", "Annual standard deduction you'd take otherwise (e.g., 29.2K married filing jointly). Inflated", defaults),
+				makeOptionalField("other_itemized", "Other Itemized ($)", "Annual itemized deductions besides mortgage interest and state and local taxes (e.g., charity). Inflated", defaults),
 				makeField("appreciation_rate", "Appreciation Rate (%)", "Annual rate (can be negative for depreciation). Comma-separated values apply to first years, last value for all remaining years (e.g., '10,5,3' = 10% yr1, 5% yr2, 3% yr3+)", defaults),
 			},
 		},
@@ -114,9 +144,26 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 				makeField("loan_rate", "Loan Rate (%)", "Annual interest rate on existing loan", defaults),
 				makeField("loan_term", "Loan Term", "Original loan duration when started (e.g., 30y)", defaults),
 				makeField("remaining_loan_term", "Remaining Loan Term", "Time left on loan (e.g., 25y)", defaults),
+				makeOptionalField("second_loan_amount", "Second Loan Balance ($)", "Optional balance owed today on a second mortgage or HELOC", defaults),
+				makeOptionalField("second_loan_rate", "Second Loan Rate (%)", "Annual interest rate on the second loan", defaults),
+				makeOptionalField("second_loan_term", "Second Loan Remaining Term", "Time left on the second loan (e.g., 8y)", defaults),
+				makeOptionalField("lump_sum_amount", "Lump-Sum Prepayment ($)", "Optional one-time extra payment toward principal. Monthly payment stays fixed, so the loan finishes early", defaults),
+				makeOptionalField("lump_sum_month", "Lump-Sum Month", "Month number from today in which the lump sum is paid (e.g., 12)", defaults),
 				makeField("annual_insurance", "Annual Tax & Insurance ($)", "Yearly costs if keeping", defaults),
 				makeField("annual_taxes", "Other Annual Costs ($)", "Taxes, HOA fees, etc. if keeping", defaults),
 				makeField("monthly_expenses", "Monthly Expenses ($)", "Monthly costs if keeping", defaults),
+				makeOptionalField("utilities_diff", "Utilities Difference ($)", "Optional extra monthly utilities if keeping vs renting (inflated). Can be negative", defaults),
+				makeOptionalField("one_time_costs", "One-Time Costs", "Optional one-off expenses if keeping as month:amount pairs from today, e.g., '12:15K,60:8K'. Quote amounts with thousands separators, e.g., '12:\"15,000\"'", defaults),
+				makeToggleField("value_based_escrow", "Value-Based Tax & Insurance", "Toggle to grow tax & insurance with the asset value instead of inflation", defaults),
+				makeOptionalField("reassess_every_years", "Reassess Tax Every (years)", "Optional interval at which tax & insurance reset to the asset value (e.g., 5). Blank means inflation only", defaults),
+				makeOptionalField("reassess_cap", "Reassessment Cap (%)", "Maximum yearly growth of tax & insurance between reassessments. Blank means 2%", defaults),
+				makeOptionalField("maintenance_pct", "Maintenance (% of value)", "Optional annual maintenance as a percent of the home's value (e.g., 1 for the 1% rule). Grows with appreciation", defaults),
+				makeToggleField("include_rental_income", "Rent Out If Keeping", "Toggle if keeping means renting the asset out", defaults),
+				makeOptionalField("monthly_rental_income", "Monthly Rental Income ($)", "Gross monthly rent received if keeping (inflated annually)", defaults),
+				makeOptionalField("vacancy_rate", "Vacancy Rate (%)", "Percent of gross rent lost to vacancy (e.g., 5)", defaults),
+				makeOptionalField("management_fee_pct", "Management Fee (%)", "Property-management fee as a percent of collected rent (e.g., 8)", defaults),
+				makeOptionalField("depreciable_basis", "Depreciable Basis ($)", "Optional structure value (excluding land) depreciated over 27.5 years while renting out; recaptured at 25% on sale. Needs a rental income tax rate", defaults),
+				makeOptionalField("rental_income_tax_rate", "Rental Income Tax (%)", "Optional income tax rate on rental income after deductible costs and depreciation. Required with a depreciable basis", defaults),
 				makeField("appreciation_rate", "Appreciation Rate (%)", "Annual rate if keeping. Comma-separated for different years", defaults),
 			},
 		},
@@ -125,9 +172,15 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Scenario: "buy_vs_rent",
 			Fields: []FormField{
 				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit", defaults),
-				makeField("monthly_rent", "Monthly Rent ($)", "Base monthly rent amount", defaults),
+				makeOptionalField("rent_broker_fee", "Broker Fee ($)", "Optional non-recoverable broker fee paid at move-in", defaults),
+				makeToggleField("first_last_month", "First & Last Month", "Toggle if the lease requires the last month's rent upfront along with the first", defaults),
+				makeField("monthly_rent", "Monthly Rent ($)", "Base monthly rent amount. Leave blank to estimate it from the price-to-rent ratio", defaults),
+				makeOptionalField("rent_step", "Rent Step", "Optional rent increase at each yearly renewal instead of inflation: dollars (e.g., 50) or percent (e.g., 5%)", defaults),
+				makeOptionalField("price_to_rent", "Price-to-Rent Ratio", "Optional purchase price / annual rent (e.g., 20). Used only when monthly rent is blank", defaults),
 				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental-related costs", defaults),
 				makeField("other_annual_costs", "Other Annual Costs ($)", "Additional yearly costs for renting", defaults),
+				makeOptionalField("moving_cost", "Moving Cost ($)", "Optional cost of each move. Buyers move once at purchase; renters at the start and every N years", defaults),
+				makeOptionalField("rent_move_frequency_years", "Renter Moves Every (years)", "How often renters move (e.g., 3). Blank means renters move only at the start", defaults),
 			},
 		},
 		{
@@ -136,8 +189,13 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 			Fields: []FormField{
 				makeToggleField("include_renting_sell", "Include Renting Analysis", "Toggle if selling means you'll need to rent", defaults),
 				makeField("rent_deposit", "Rental Deposit ($)", "Initial rental deposit if selling", defaults),
+				makeOptionalField("rent_broker_fee", "Broker Fee ($)", "Optional non-recoverable broker fee paid at move-in", defaults),
+				makeToggleField("first_last_month", "First & Last Month", "Toggle if the lease requires the last month's rent upfront along with the first", defaults),
 				makeField("monthly_rent", "Monthly Rent ($)", "Monthly rent if selling", defaults),
+				makeOptionalField("rent_step", "Rent Step", "Optional rent increase at each yearly renewal instead of inflation: dollars (e.g., 50) or percent (e.g., 5%)", defaults),
 				makeField("annual_rent_costs", "Annual Rent Costs ($)", "Yearly rental costs if selling", defaults),
+				makeOptionalField("moving_cost", "Moving Cost ($)", "Optional cost of each move if selling and renting", defaults),
+				makeOptionalField("rent_move_frequency_years", "Renter Moves Every (years)", "How often you'd move while renting (e.g., 3). Blank means only the initial move", defaults),
 			},
 		},
 		{
@@ -147,8 +205,15 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 				makeToggleField("include_selling", "Include Selling Analysis", "Toggle to enable/disable selling analysis (BUY vs RENT only)", defaults),
 				makeField("agent_commission", "Agent Commission (%)", "Percentage of sale price paid to agents", defaults),
 				makeField("staging_costs", "Staging/Selling Costs ($)", "Fixed costs to prepare and sell", defaults),
-				makeField("tax_free_limit", "Tax-Free Gains Limit ($)", "Capital gains exempt from tax. Comma-separated for different years (e.g., '500K,0K' = 500K year 1, 0 year 2+)", defaults),
+				makeOptionalField("transfer_tax_pct", "Transfer Tax (%)", "Optional real-estate transfer tax as a percent of sale price", defaults),
+				makeOptionalField("seller_concessions", "Seller Concessions ($)", "Optional concessions given to the buyer at sale (e.g., closing credits)", defaults),
+				makeOptionalField("prepay_penalty_pct", "Prepayment Penalty (%)", "Optional penalty as a percent of the loan payoff if sold early", defaults),
+				makeOptionalField("prepay_penalty_years", "Prepayment Penalty Years", "Years the penalty applies, from purchase (BUY vs RENT) or from today (SELL vs KEEP)", defaults),
+				makeField("tax_free_limit", "Tax-Free Gains Limit ($)", "Capital gains exempt from tax. Comma-separated for different years (e.g., '500K,0K' = 500K year 1, 0 year 2+). Quote numbers with thousands separators, e.g., '\"1,250,000\",0'", defaults),
+				makeToggleFieldWithValue("primary_residence", "Primary Residence", "Toggle off if not your primary residence. The tax-free limit needs 2 of the last 5 years lived in", defaults["primary_residence"] != "0"),
+				makeOptionalField("months_occupied", "Months Occupied", "Months you've already lived in the home as of today. Blank means 0 for BUY vs RENT (a new purchase) and the full 5 years for SELL vs KEEP", defaults),
 				makeField("capital_gains_tax", "Capital Gains Tax Rate (%)", "Long-term capital gains tax rate", defaults),
+				makeOptionalField("short_term_rate", "Short-Term Gains Rate (%)", "Optional rate for sales within 12 months of purchase (ordinary income). Blank uses the long-term rate", defaults),
 			},
 		},
 	}
@@ -188,12 +253,21 @@ func NewFormModel(defaults map[string]string, md *MarketData) FormModel {
 	}
 }
 
+// knownInputKeys returns the set of input keys used by the form across both scenarios
+func knownInputKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for key := range NewFormModel(map[string]string{}, nil).fieldsMap {
+		keys[key] = true
+	}
+	return keys
+}
+
 func makeField(key, label, help string, defaults map[string]string) FormField {
 	ti := textinput.New()
 	ti.Placeholder = "0"
 	ti.CharLimit = 32
 	ti.Width = 30  // Fixed width to prevent jumping
-	ti.Prompt = ""  // Disable built-in prompt, we'll use our own caret in the label
+	ti.Prompt = "" // Disable built-in prompt, we'll use our own caret in the label
 	ti.TextStyle = lipgloss.NewStyle().Foreground(MonokaiAdaptiveText)
 	ti.Cursor.Style = focusedStyle
 
@@ -208,9 +282,153 @@ func makeField(key, label, help string, defaults map[string]string) FormField {
 		Input:    ti,
 		Required: true,
 		IsToggle: false,
+		Validate: validatorForKey(key),
+	}
+}
+
+// validatorForKey returns the parser-backed validator for a text field
+func validatorForKey(key string) func(string) error {
+	switch key {
+	case "loan_term", "remaining_loan_term", "second_loan_term", "balloon_term":
+		return func(value string) error {
+			if _, err := calc.ParseDuration(value); err != nil {
+				return fmt.Errorf("not a valid duration (e.g., 30y, 5y6m, 6m): %v", err)
+			}
+			return nil
+		}
+	case "one_time_costs":
+		return func(value string) error {
+			_, err := parseOneTimeCosts(value)
+			return err
+		}
+	case "rent_step":
+		return func(value string) error {
+			_, _, err := parseRentStep(value)
+			return err
+		}
+	case "inflation_rate", "investment_return_rate", "appreciation_rate", "tax_free_limit", "buydown":
+		return func(value string) error {
+			_, err := calc.ParseAppreciationRates(value)
+			return err
+		}
+	default:
+		return func(value string) error {
+			if _, err := calc.ParseAmount(value); err != nil {
+				return fmt.Errorf("not a valid number (e.g., 500K, 6.5, -4K)")
+			}
+			return nil
+		}
 	}
 }
 
+// fieldError returns the validation error for a field's current value, or nil if valid or empty
+func (f *FormField) fieldError() error {
+	if f.IsToggle || f.Validate == nil {
+		return nil
+	}
+	value := strings.TrimSpace(f.Input.Value())
+	if value == "" {
+		return nil
+	}
+	return f.Validate(value)
+}
+
+// fieldValue returns the trimmed text of the field with key, or "" if there's no such field
+func (m FormModel) fieldValue(key string) string {
+	if field, ok := m.fieldsMap[key]; ok {
+		return strings.TrimSpace(field.Input.Value())
+	}
+	return ""
+}
+
+// fieldMissing reports whether a required text field was left blank
+// Rent may be blank when a price-to-rent ratio estimates it, the loan amount when a downpayment percentage sets it
+// (BUY vs RENT), and the loan rate and term when there's no loan (with no BUY vs RENT downpayment percentage either)
+func (m FormModel) fieldMissing(field *FormField) bool {
+	if !field.Required || field.IsToggle || strings.TrimSpace(field.Input.Value()) != "" {
+		return false
+	}
+	switch field.Key {
+	case "loan_amount":
+		return m.selectedScenario() == "sell_vs_keep" || m.fieldValue("downpayment_pct") == ""
+	case "monthly_rent":
+		return m.fieldValue("price_to_rent") == ""
+	case "loan_rate", "loan_term", "remaining_loan_term":
+		loanAmount, err := calc.ParseAmount(m.fieldValue("loan_amount"))
+		downpaymentSet := m.selectedScenario() == "buy_vs_rent" && m.fieldValue("downpayment_pct") != ""
+		return err != nil || loanAmount > 0 || downpaymentSet
+	}
+	return true
+}
+
+// fieldToggledOff reports whether the field at index belongs to an analysis that's toggled off, so it may be left blank:
+// the selling group when BUY vs RENT excludes selling, and the renting fields when SELL vs KEEP excludes renting
+func (m FormModel) fieldToggledOff(fieldIndex int) bool {
+	group, _ := m.groupAt(fieldIndex)
+	if group == nil {
+		return false
+	}
+	toggleKey := ""
+	switch {
+	case group.Name == "SELLING" && m.selectedScenario() == "buy_vs_rent":
+		toggleKey = "include_selling"
+	case group.Name == "INVESTING":
+		toggleKey = "include_renting_sell"
+	default:
+		return false
+	}
+	toggle, ok := m.fieldsMap[toggleKey]
+	return ok && !toggle.Toggled && m.fields[fieldIndex].Key != toggleKey
+}
+
+// firstInvalidField returns the index of the first visible field that is blank but required or fails validation,
+// with an error naming the field; returns -1 and nil if every field is fine
+func (m FormModel) firstInvalidField() (int, error) {
+	for i, field := range m.fields {
+		if !m.isFieldVisible(i) {
+			continue
+		}
+		label := m.groupFieldAt(i).Label
+		if m.fieldMissing(field) && !m.fieldToggledOff(i) {
+			return i, fmt.Errorf("%s is required", label)
+		}
+		if err := field.fieldError(); err != nil {
+			return i, fmt.Errorf("%s: %v", label, err)
+		}
+	}
+	return -1, nil
+}
+
+// groupStartIndex returns the field index of the first field in the Nth (1-based) visible group, or -1
+func (m FormModel) groupStartIndex(groupNumber int) int {
+	fieldIndex := 0
+	visibleGroups := 0
+	for _, group := range m.groups {
+		if len(group.Fields) > 0 && m.isFieldVisible(fieldIndex) {
+			visibleGroups++
+			if visibleGroups == groupNumber {
+				return fieldIndex
+			}
+		}
+		fieldIndex += len(group.Fields)
+	}
+	return -1
+}
+
+// focusField moves focus to the field at index
+func (m *FormModel) focusField(index int) {
+	m.fields[m.currentField].Input.Blur()
+	m.currentField = index
+	m.fields[m.currentField].Input.Focus()
+}
+
+// makeOptionalField creates a text field that may be left blank
+func makeOptionalField(key, label, help string, defaults map[string]string) FormField {
+	field := makeField(key, label, help, defaults)
+	field.Required = false
+	return field
+}
+
 func makeToggleField(key, label, help string, defaults map[string]string) FormField {
 	ti := textinput.New()
 	ti.Width = 30
@@ -250,31 +468,46 @@ func (m FormModel) Init() tea.Cmd {
 	return textinput.Blink
 }
 
-// isFieldVisible checks if a field at the given index is visible in the current scenario
-func (m FormModel) isFieldVisible(fieldIndex int) bool {
-	if fieldIndex < 0 || fieldIndex >= len(m.fields) {
-		return false
-	}
-
-	// Determine current scenario
-	selectedScenario := "buy_vs_rent" // default
+// selectedScenario returns "sell_vs_keep" when that scenario is toggled on, otherwise "buy_vs_rent"
+func (m FormModel) selectedScenario() string {
 	if scenarioField, ok := m.fieldsMap["scenario_sell_vs_keep"]; ok && scenarioField.Toggled {
-		selectedScenario = "sell_vs_keep"
+		return "sell_vs_keep"
 	}
+	return "buy_vs_rent"
+}
 
-	// Find which group this field belongs to
+// groupAt returns the group holding the field at the given index and the field's position within it,
+// or nil if the index is out of range
+func (m FormModel) groupAt(fieldIndex int) (*FieldGroup, int) {
+	if fieldIndex < 0 {
+		return nil, 0
+	}
 	currentIndex := 0
-	for _, group := range m.groups {
-		for range group.Fields {
-			if currentIndex == fieldIndex {
-				// Check if this group is visible in current scenario
-				return group.Scenario == "both" || group.Scenario == selectedScenario
-			}
-			currentIndex++
+	for i := range m.groups {
+		if fieldIndex < currentIndex+len(m.groups[i].Fields) {
+			return &m.groups[i], fieldIndex - currentIndex
 		}
+		currentIndex += len(m.groups[i].Fields)
+	}
+	return nil, 0
+}
+
+// groupFieldAt returns the group's own definition of the field at index, falling back to the shared instance
+// Keys shared by both scenarios keep one value in m.fields, but each scenario's group has its own label and help
+func (m FormModel) groupFieldAt(fieldIndex int) *FormField {
+	if group, offset := m.groupAt(fieldIndex); group != nil {
+		return &group.Fields[offset]
 	}
+	return m.fields[fieldIndex]
+}
 
-	return false
+// isFieldVisible checks if a field at the given index is visible in the current scenario
+func (m FormModel) isFieldVisible(fieldIndex int) bool {
+	if fieldIndex >= len(m.fields) {
+		return false
+	}
+	group, _ := m.groupAt(fieldIndex)
+	return group != nil && (group.Scenario == "both" || group.Scenario == m.selectedScenario())
 }
 
 func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -287,7 +520,8 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleLoadDialog(msg)
 		}
 
-		// Normal mode key handling
+		// Normal mode key handling; any key clears the last submit error
+		m.err = nil
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
@@ -310,7 +544,7 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.dialogInput.Width = 40
 			return m, nil
 
-		case "ctrl+o":
+		case "ctrl+o", "ctrl+l":
 			// Open load dialog
 			profiles, err := listProfiles()
 			if err != nil {
@@ -322,6 +556,14 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.selectedProfile = 0
 			return m, nil
 
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			// Jump to the first field of the Nth visible group
+			groupNumber := int(msg.String()[len("alt+")] - '0')
+			if index := m.groupStartIndex(groupNumber); index != -1 {
+				m.focusField(index)
+			}
+			return m, nil
+
 		case "ctrl+t":
 			// Toggle between scenarios
 			if buyField, ok := m.fieldsMap["scenario_buy_vs_rent"]; ok {
@@ -334,6 +576,14 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "ctrl+k":
+			// Refuse to submit while a visible field is missing or invalid
+			m.submitTried = true
+			if invalid, err := m.firstInvalidField(); invalid != -1 {
+				m.focusField(invalid)
+				m.err = err
+				return m, nil
+			}
+
 			// Save values and submit
 			for _, field := range m.fields {
 				if field.IsToggle {
@@ -405,8 +655,12 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Update the focused input field (but not if it's a toggle)
 	var cmd tea.Cmd
-	if !m.fields[m.currentField].IsToggle {
-		m.fields[m.currentField].Input, cmd = m.fields[m.currentField].Input.Update(msg)
+	if field := m.fields[m.currentField]; !field.IsToggle {
+		before := field.Input.Value()
+		field.Input, cmd = field.Input.Update(msg)
+		if field.Input.Value() != before {
+			field.Edited = true
+		}
 	}
 	return m, cmd
 }
@@ -419,13 +673,7 @@ func (m FormModel) View() string {
 	var b strings.Builder
 
 	// Determine which scenario is currently selected
-	selectedScenario := "buy_vs_rent" // default
-	for _, field := range m.fields {
-		if field.Key == "scenario_sell_vs_keep" && field.Toggled {
-			selectedScenario = "sell_vs_keep"
-			break
-		}
-	}
+	selectedScenario := m.selectedScenario()
 
 	// Title
 	b.WriteString(titleStyle.Render("┌────────────────────────────────────────────────────────────────┐"))
@@ -435,8 +683,9 @@ func (m FormModel) View() string {
 	b.WriteString(titleStyle.Render("└────────────────────────────────────────────────────────────────┘"))
 	b.WriteString("\n\n")
 
-	// Track field index as we render groups
+	// Track field index and visible group number as we render groups
 	fieldIndex := 0
+	groupNumber := 0
 
 	// Render each group
 	for groupIdx, group := range m.groups {
@@ -445,14 +694,16 @@ func (m FormModel) View() string {
 			fieldIndex += len(group.Fields)
 			continue
 		}
-		// Group header
-		b.WriteString(groupStyle.Render("  " + group.Name))
+		groupNumber++
+
+		// Group header (numbered for Alt+N jumps)
+		b.WriteString(groupStyle.Render(fmt.Sprintf("  %d. %s", groupNumber, group.Name)))
 		b.WriteString("\n")
 
 		// Render fields in this group (label and input on same line)
 		for i := 0; i < len(group.Fields); i++ {
 			currentFieldIndex := fieldIndex + i
-			// Get field from fieldsMap to ensure we're using shared instances
+			// Values come from the shared instance in fieldsMap; the label is this group's own
 			groupField := &group.Fields[i]
 			field := m.fieldsMap[groupField.Key]
 
@@ -471,7 +722,7 @@ func (m FormModel) View() string {
 			// Print label and input on same line with matching colors
 			if currentFieldIndex == m.currentField {
 				// Focused: entire line is pink with caret
-				labelText := fmt.Sprintf("%-50s", "❯ "+field.Label)
+				labelText := fmt.Sprintf("%-50s", "❯ "+groupField.Label)
 				b.WriteString(focusedStyle.Render(labelText))
 				if field.IsToggle {
 					b.WriteString(focusedStyle.Render(input))
@@ -481,7 +732,7 @@ func (m FormModel) View() string {
 				}
 			} else {
 				// Not focused: no caret on label, but caret before input value
-				labelText := fmt.Sprintf("%-50s", "  "+field.Label)
+				labelText := fmt.Sprintf("%-50s", "  "+groupField.Label)
 				b.WriteString(blurredStyle.Render(labelText))
 				if field.IsToggle {
 					b.WriteString(blurredStyle.Render(input))
@@ -492,18 +743,39 @@ func (m FormModel) View() string {
 			}
 			b.WriteString("\n")
 
+			// Show validation error below an invalid or missing field
+			if err := field.fieldError(); err != nil {
+				b.WriteString(errorStyle.Render("    ✗ " + err.Error()))
+				b.WriteString("\n")
+			} else if (m.submitTried || field.Edited) && !m.fieldToggledOff(currentFieldIndex) && m.fieldMissing(field) {
+				b.WriteString(errorStyle.Render("    ✗ required"))
+				b.WriteString("\n")
+			}
+
+			// Show live monthly payment after the loan term in the BUYING group
+			if field.Key == "loan_term" && group.Scenario == "buy_vs_rent" {
+				b.WriteString(helpStyle.Render("    Monthly Payment: " + m.liveMonthlyPayment()))
+				b.WriteString("\n")
+			}
+
+			// Show the fetched CPI inflation rate after the inflation rate field
+			if field.Key == "inflation_rate" && cpiInflation != nil {
+				b.WriteString(helpStyle.Render("    " + cpiFootnote(cpiInflation)))
+				b.WriteString("\n")
+			}
+
 			// Show market averages after investment return rate field
 			if field.Key == "investment_return_rate" && m.marketData != nil && len(m.marketData.VOO) > 0 {
 				vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(m.marketData)
 				if vooAvg > 0 {
 					tickerStyle := lipgloss.NewStyle().Foreground(MonokaiCyan)
 					prefix := helpStyle.Render("    Market Averages (10y): ")
-					tickers := fmt.Sprintf("%s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%",
+					tickers := fmt.Sprintf("%s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%%s",
 						tickerStyle.Render("VOO"), vooAvg,
 						tickerStyle.Render("QQQ"), qqqAvg,
 						tickerStyle.Render("VTI"), vtiAvg,
 						tickerStyle.Render("BND"), bndAvg,
-						tickerStyle.Render("60/40"), mix6040Avg)
+						tickerStyle.Render("60/40"), mix6040Avg, mixAverageText(m.marketData, tickerStyle))
 					b.WriteString(prefix + tickers)
 					b.WriteString("\n")
 				}
@@ -519,16 +791,22 @@ func (m FormModel) View() string {
 		fieldIndex += len(group.Fields)
 	}
 
-	// Show help text for current field at the bottom
-	currentField := m.fields[m.currentField]
+	// Show help text for current field at the bottom, as its group words it for the selected scenario
+	currentField := m.groupFieldAt(m.currentField)
 	b.WriteString("\n")
-	// Wrap help text at 80 characters with left padding for indentation
-	helpTextStyle := helpStyle.Copy().Width(80).PaddingLeft(2)
+	// Wrap help text at the note width with left padding for indentation
+	helpTextStyle := helpStyle.Copy().Width(wrapWidth()).PaddingLeft(2)
 	b.WriteString(helpTextStyle.Render(currentField.Help))
 	b.WriteString("\n\n")
 
+	// Explain why the last Ctrl+K didn't submit
+	if m.err != nil {
+		b.WriteString(errorStyle.Render("  ✗ Can't calculate yet: " + m.err.Error()))
+		b.WriteString("\n\n")
+	}
+
 	// Navigation help
-	b.WriteString(helpStyle.Render("  ↑/↓: Navigate  Space/Enter: Toggle  Ctrl+T: Switch Scenario  Ctrl+S: Save  Ctrl+O: Load  Ctrl+K: Calculate  Ctrl+C/Esc: Quit"))
+	b.WriteString(helpStyle.Render("  ↑/↓: Navigate  Alt+1-9: Jump to Group  Space/Enter: Toggle  Ctrl+T: Switch Scenario  Ctrl+S: Save  Ctrl+O/L: Load  Ctrl+K: Calculate  Ctrl+C/Esc: Quit"))
 	b.WriteString("\n")
 
 	// Show dialog overlays
@@ -542,6 +820,50 @@ func (m FormModel) View() string {
 	return result
 }
 
+// liveMonthlyPayment computes the loan payment from the current form values, or "-" if any input is missing or invalid
+func (m FormModel) liveMonthlyPayment() string {
+	value := func(key string) string {
+		if field, ok := m.fieldsMap[key]; ok {
+			return strings.TrimSpace(field.Input.Value())
+		}
+		return ""
+	}
+
+	amountStr, rateStr, termStr := value("loan_amount"), value("loan_rate"), value("loan_term")
+
+	// A downpayment percentage overrides the loan amount, as in parseConfig
+	if pctStr := value("downpayment_pct"); pctStr != "" {
+		pct, err := calc.ParseAmount(pctStr)
+		if err != nil {
+			return "-"
+		}
+		price, err := calc.ParseAmount(value("purchase_price"))
+		if err != nil {
+			return "-"
+		}
+		amountStr = fmt.Sprintf("%f", price*(1-pct/100))
+	}
+
+	if amountStr == "" || rateStr == "" || termStr == "" {
+		return "-"
+	}
+
+	loanAmount, err := calc.ParseAmount(amountStr)
+	if err != nil || loanAmount <= 0 {
+		return "-"
+	}
+	annualRate, err := calc.ParseAmount(rateStr)
+	if err != nil {
+		return "-"
+	}
+	months, err := calc.ParseDuration(termStr)
+	if err != nil {
+		return "-"
+	}
+
+	return formatCurrency(calc.MonthlyPayment(loanAmount, annualRate/100/12, months))
+}
+
 // handleSaveDialog handles key presses in save dialog mode
 func (m FormModel) handleSaveDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -574,7 +896,7 @@ func (m FormModel) handleSaveDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		// Save profile
-		if err := saveProfile(profileName, values); err != nil {
+		if err := saveProfile(profileName, values, ""); err != nil {
 			// Could show error, but for now just close
 			m.dialogMode = ModeNormal
 			return m, nil
@@ -637,6 +959,11 @@ func (m FormModel) handleLoadDialog(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The profile may switch scenarios; keep focus on a visible field
+		if !m.isFieldVisible(m.currentField) {
+			m.focusField(0)
+		}
+
 		// Close dialog
 		m.dialogMode = ModeNormal
 		return m, nil
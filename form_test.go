@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// completeBuyVsRentInputs returns every required BUY vs RENT field filled in
+func completeBuyVsRentInputs() map[string]string {
+	return map[string]string{
+		"inflation_rate":         "3",
+		"investment_return_rate": "10",
+		"purchase_price":         "1.32M",
+		"loan_amount":            "1.056M",
+		"loan_rate":              "5.5",
+		"loan_term":              "30y",
+		"annual_insurance":       "19K",
+		"annual_taxes":           "10K",
+		"monthly_expenses":       "0",
+		"appreciation_rate":      "4",
+		"rent_deposit":           "10K",
+		"monthly_rent":           "5K",
+		"annual_rent_costs":      "3K",
+		"other_annual_costs":     "0",
+		"include_selling":        "1",
+		"agent_commission":       "6",
+		"staging_costs":          "15K",
+		"tax_free_limit":         "500K",
+		"capital_gains_tax":      "33",
+	}
+}
+
+func TestFirstInvalidField(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes map[string]string
+		invalid string // Key of the field that blocks submit, or "" if none
+	}{
+		{"complete", nil, ""},
+		{"blank loan amount", map[string]string{"loan_amount": ""}, "loan_amount"},
+		{"downpayment sets the loan", map[string]string{"loan_amount": "", "downpayment_pct": "20"}, ""},
+		{"blank commission", map[string]string{"agent_commission": ""}, "agent_commission"},
+		{"selling off", map[string]string{"include_selling": "0", "agent_commission": "", "tax_free_limit": ""}, ""},
+		{"invalid while selling off", map[string]string{"include_selling": "0", "agent_commission": "abc"}, "agent_commission"},
+		{"sell vs keep without renting", map[string]string{
+			"scenario_sell_vs_keep": "1", "current_market_value": "2M", "remaining_loan_term": "25y",
+			"include_renting_sell": "0", "rent_deposit": "", "monthly_rent": "", "annual_rent_costs": "",
+		}, ""},
+		{"sell vs keep paid off with a stale downpayment", map[string]string{
+			"scenario_sell_vs_keep": "1", "current_market_value": "2M", "include_renting_sell": "0",
+			"downpayment_pct": "20", "loan_amount": "0", "loan_rate": "", "loan_term": "", "remaining_loan_term": "",
+		}, ""},
+		{"sell vs keep with renting", map[string]string{
+			"scenario_sell_vs_keep": "1", "current_market_value": "2M", "remaining_loan_term": "25y",
+			"include_renting_sell": "1", "monthly_rent": "",
+		}, "monthly_rent"},
+	}
+	for _, tt := range tests {
+		defaults := completeBuyVsRentInputs()
+		for key, value := range tt.changes {
+			defaults[key] = value
+		}
+		m := NewFormModel(defaults, nil)
+		index, err := m.firstInvalidField()
+		got := ""
+		if index != -1 {
+			got = m.fields[index].Key
+		}
+		if got != tt.invalid {
+			t.Errorf("%s: firstInvalidField = %q (%v), want %q", tt.name, got, err, tt.invalid)
+		}
+	}
+}
+
+func TestRequiredMarkerAfterSubmit(t *testing.T) {
+	defaults := completeBuyVsRentInputs()
+	defaults["loan_amount"] = ""
+	var model tea.Model = NewFormModel(defaults, nil)
+	if strings.Contains(model.View(), "✗ required") {
+		t.Error("a blank required field is flagged before any edit or submit")
+	}
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if !strings.Contains(model.View(), "✗ required") {
+		t.Error("a blank required field isn't flagged after a submit attempt")
+	}
+}
+
+func TestScenarioLabels(t *testing.T) {
+	defaults := completeBuyVsRentInputs()
+	defaults["scenario_sell_vs_keep"] = "1"
+	m := NewFormModel(defaults, nil)
+	view := m.View()
+	for _, label := range []string{"Original Loan Amount ($)", "Second Loan Balance ($)", "Second Loan Remaining Term"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("SELL vs KEEP form is missing the %q label", label)
+		}
+	}
+	for i, field := range m.fields {
+		if field.Key == "lump_sum_month" && m.isFieldVisible(i) {
+			if help := m.groupFieldAt(i).Help; !strings.Contains(help, "from today") {
+				t.Errorf("SELL vs KEEP lump_sum_month help = %q, want the from-today wording", help)
+			}
+		}
+	}
+}
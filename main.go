@@ -2,16 +2,24 @@ package main
 
 import (
 	"bufio"
+	"encoding/csv"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"calculator/calc"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/term"
 )
 
 var reader = bufio.NewReader(os.Stdin)
@@ -19,59 +27,109 @@ var savedDefaults map[string]string
 var currentInputs map[string]string
 var useDefaults bool
 var fullNumbers bool
+var negParens bool
+var precision int
+var trimZeros bool
+
+// hideZeros is set by the --hide-zeros flag
+var hideZeros bool
+var periodsSpec string
+var customPeriods []int // Table periods in months from --periods (empty = defaults)
+var tableResolution string
+var horizonYears = 30
+var amortFull bool
+var csvPath string
+
+// wrapWidthFlag is set by the --width flag (0 = detect from the terminal)
+var wrapWidthFlag int
+
+// cashflowCSVPath is set by the --cashflow-csv flag
+var cashflowCSVPath string
+var profileName string
+var saveProfileName string
+var deleteProfileName string
+var renameProfileSpec string
+
+// profileDescription is set by the --description flag
+var profileDescription string
+
+// listProfilesOnly is set by the --list-profiles flag
+var listProfilesOnly bool
+var forceOverwrite bool
+var inputsPath string
+var saveInputsPath string
+var readStdin bool
+var realDollars bool
+var displayUnits string
+
+// displayCurrency is set by the --display-currency flag
+var displayCurrency string
+
+// fxRate is set by the --fx flag: display-currency units per unit of the input currency
+var fxRate = 1.0
+
+// localeName is set by the --locale flag
+var localeName = "en-US"
+
+// numberLocale describes how full numbers are grouped and where the currency symbol goes
+type numberLocale struct {
+	thousands   string
+	decimal     string
+	symbolAfter bool // e.g. "1.250.000,00 €" rather than "€1,250,000.00"
+}
+
+// numberLocales are the supported --locale values
+var numberLocales = map[string]numberLocale{
+	"en-US": {thousands: ",", decimal: ".", symbolAfter: false},
+	"de-DE": {thousands: ".", decimal: ",", symbolAfter: true},
+	"fr-FR": {thousands: "\u202f", decimal: ",", symbolAfter: true},
+}
+var showChart bool
+var monteCarloTrials int
+var historyTicker string
+var monteCarloSeed int64
+var backtestStartYear int
+var sensitivitySpec string
+var showTornado bool
+
+// fetchCPI is set by the --cpi flag
+var fetchCPI bool
+
+// overrideSpec is set by the --override flag
+var overrideSpec string
+
+// mixSpec is set by the --mix flag
+var mixSpec string
+
+// offlineMode is set by the --offline flag
+var offlineMode bool
+
+// quietOutput is set by the --quiet flag
+var quietOutput bool
 
-// Global arrays for monthly costs
-var monthlyBuyingCosts []float64
-var monthlyRentingCosts []float64
-var remainingLoanBalance []float64
-var cumulativePrincipalPaid []float64
-var cumulativeInterestPaid []float64
-var appreciationRates []float64 // Annual appreciation rates
-var taxFreeLimits []float64     // Tax-free capital gains limits by year
-
-// Global arrays for KEEP scenario investment tracking
-var monthlyKeepInvestmentValue []float64 // Investment value at each month
-var monthlyKeepRealCosts []float64       // Cumulative real out-of-pocket costs at each month
-var monthlyKeepNetPosition []float64     // Net position (investment - real costs) at each month
-
-// Config holds all input parameters
-type Config struct {
-	// Economic
-	inflationRate float64
-	include30Year float64
-
-	// Buying/Asset
-	purchasePrice      float64 // Original purchase price (for capital gains)
-	currentMarketValue float64 // Current value (for SELL vs KEEP)
-	downpayment        float64
-	loanAmount         float64
-	annualRate         float64
-	totalMonths        int
-	monthlyRate        float64
-	monthlyLoanPayment float64
-	annualInsurance    float64
-	annualTaxes        float64
-	monthlyExpenses    float64
-	totalMonthlyBuyingCost float64
-
-	// Renting
-	rentDeposit            float64
-	monthlyRent            float64
-	annualRentCosts        float64
-	otherAnnualCosts       float64
-	investmentReturnRate   float64
-	totalMonthlyRentingCost float64
-
-	// Selling
-	includeSelling  float64
-	agentCommission float64
-	stagingCosts    float64
-	capitalGainsTax float64
-}
-
-var config Config
-
-const inputsFile = ".rentobuy_inputs.json"
+// showYearlyDelta is set by the --yearly-delta flag
+var showYearlyDelta bool
+
+// replMode is set by the --repl flag
+var replMode bool
+
+// monthlyAppreciation is set by the --monthly-appreciation flag
+var monthlyAppreciation bool
+
+// inflationTiming is set by the --inflation-timing flag: "anniversary" (default) or "start"
+// "anniversary" is the original schedule: costs stay flat through year one and rise by the past year's rate at
+// i%12==0 from month 13 on, which is both the start of each later year and a purchase anniversary. "start" applies
+// each year's own rate from its first month, year one included, so it is the mode that shifts every period
+var inflationTiming = "anniversary"
+var backtestReturns []float64 // Historical returns by projection year (from --backtest-start)
+
+// monthly is the projection of the current config, set by populateMonthlyCosts
+var monthly *calc.Results
+
+var config calc.Inputs
+
+// exitError is the exit code for invalid flags or inputs and failed commands, distinct from the verdict's 0 and 1
+const exitError = 2
 
 func main() {
 	// Clear screen
@@ -80,18 +138,240 @@ func main() {
 	// Parse command line flags
 	flag.BoolVar(&useDefaults, "defaults", false, "Use all previously saved default values without prompting")
 	flag.BoolVar(&fullNumbers, "full-numbers", false, "Display full numbers instead of compact K/M format")
+	flag.BoolVar(&negParens, "neg-parens", false, "Display negative amounts in parentheses (accounting style) instead of with a minus sign")
+	flag.IntVar(&precision, "precision", 1, "Number of decimal places in displayed amounts (0-6)")
+	flag.IntVar(&wrapWidthFlag, "width", 0, "Wrap notes and help text at N columns (default: terminal width, or 100 if unknown)")
+	flag.BoolVar(&hideZeros, "hide-zeros", false, "Drop table columns whose values are all zero (e.g., Loan Payoff for an all-cash purchase)")
+	flag.BoolVar(&trimZeros, "trim-zeros", false, "Trim trailing zeros from displayed amounts (e.g., 1.50K becomes 1.5K)")
+	flag.StringVar(&periodsSpec, "periods", "", "Comma-separated table periods instead of the defaults (e.g., 3y,7y,12y,25y)")
+	flag.IntVar(&horizonYears, "horizon", 30, "Projection horizon in years (sizes all monthly projections)")
+	flag.StringVar(&tableResolution, "resolution", "yearly", "Table period resolution for the first 2 years: monthly, quarterly, or yearly")
+	flag.BoolVar(&amortFull, "amort-full", false, "Display the full month-by-month amortization schedule")
+	flag.StringVar(&csvPath, "csv", "", "Write the full amortization schedule to this CSV file (with --amort-full)")
+	flag.StringVar(&profileName, "profile", "", "Use inputs from the named saved profile without prompting")
+	flag.StringVar(&saveProfileName, "save-profile", "", "Save this run's inputs to the named profile")
+	flag.StringVar(&profileDescription, "description", "", "Short description stored with --save-profile")
+	flag.BoolVar(&listProfilesOnly, "list-profiles", false, "List saved profiles with their created and modified dates and description, and exit")
+	flag.StringVar(&deleteProfileName, "delete-profile", "", "Delete the named profile and exit")
+	flag.StringVar(&renameProfileSpec, "rename-profile", "", "Rename a profile and exit (format: old:new)")
+	flag.BoolVar(&forceOverwrite, "force", false, "Allow --rename-profile to overwrite an existing profile")
+	flag.StringVar(&inputsPath, "inputs", "", "Read saved inputs from this JSON file (default: inputs.json in the user config dir, e.g. ~/.config/rentobuy)")
+	flag.StringVar(&saveInputsPath, "save-inputs", "", "Write this run's inputs to this JSON file (default: inputs.json in the user config dir)")
+	flag.BoolVar(&readStdin, "stdin", false, "Read key=value inputs from stdin without prompting")
+	flag.BoolVar(&copyToClipboard, "clipboard", false, "Copy the comparison table as plain text (no colors) to the system clipboard")
+	flag.StringVar(&cashflowCSVPath, "cashflow-csv", "", "Write every month's buying cost, renting cost, investment value, net position and loan balance to this CSV file")
+	flag.StringVar(&htmlReportPath, "html", "", "Also write all tables to this self-contained HTML report file")
+	flag.BoolVar(&takeSnapshot, "snapshot", false, "Save this run's per-period net worth and verdict to a timestamped snapshot in the config dir")
+	flag.BoolVar(&diffLastSnapshot, "diff-last", false, "Compare this run against the most recent snapshot, showing which periods moved and by how much")
+	flag.BoolVar(&markdownOutput, "md", false, "Print tables as GitHub-flavored Markdown (## headings, pipe tables, no color)")
+	flag.BoolVar(&plainOutput, "plain", false, "Print tables as plain tab-separated rows (default when stdout is not a terminal)")
+	flag.StringVar(&themeName, "theme", "dark", "Color theme: dark, light, or auto (detect the terminal background)")
+	flag.StringVar(&borderStyle, "border", "", "Table border style: normal, rounded, double, thick, ascii, or none")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colors and box-drawing borders (also honors NO_COLOR)")
+	flag.BoolVar(&realDollars, "real", false, "Display comparison tables in today's dollars (deflated by inflation)")
+	flag.StringVar(&displayUnits, "units", "western", "Compact number units: western (K/M) or indian (L/Cr/LCr)")
+	flag.StringVar(&displayCurrency, "display-currency", "", "Currency code to display amounts in (e.g., EUR); requires --fx")
+	flag.StringVar(&localeName, "locale", localeName, "Digit grouping, decimal mark and symbol placement for --full-numbers: en-US, de-DE, or fr-FR")
+	flag.Float64Var(&fxRate, "fx", 1, "Exchange rate for --display-currency: display units per unit of the input currency (e.g., 0.92); required with --display-currency")
+	flag.BoolVar(&showChart, "chart", false, "Display an ASCII net worth chart after the comparison table")
+	flag.IntVar(&monteCarloTrials, "montecarlo", 0, "Run N Monte Carlo trials of renting net worth using bootstrapped historical returns")
+	flag.DurationVar(&marketDataTTL, "market-ttl", marketDataTTL, "How long cached market data stays fresh before refetching (e.g., 168h; 0 = every run)")
+	flag.BoolVar(&fetchCPI, "cpi", false, "Fetch trailing 12-month CPI inflation from FRED and prefill the form's inflation rate")
+	flag.StringVar(&overrideSpec, "override", "", "Save manual market returns that replace fetched ones (e.g., VOO:2022:-18.1,QQQ:2021:27)")
+	flag.StringVar(&mixSpec, "mix", "", "Custom portfolio blend shown alongside 60/40 in market data (e.g., VTI:0.5,BND:0.3,QQQ:0.2)")
+	flag.BoolVar(&offlineMode, "offline", false, "Never fetch market data; use the cached data, if any")
+	flag.StringVar(&historyTicker, "ticker", "VOO", "Ticker whose historical returns are used by --montecarlo and --backtest-start (VOO, QQQ, VTI, BND)")
+	flag.Int64Var(&monteCarloSeed, "seed", 0, "Random seed for --montecarlo (0 = random)")
+	flag.IntVar(&backtestStartYear, "backtest-start", 0, "Use actual historical returns starting from this year (YYYY) for the renting investment")
+	flag.StringVar(&sensitivitySpec, "sensitivity", "", "Display a 10-year (or shorter --horizon) RENT - BUY grid over two variables (e.g., appreciation,investment)")
+	flag.BoolVar(&quietOutput, "quiet", false, "Print only the comparison table and the verdict line, skipping inputs, market data, amortization and expense breakdowns (exit code: 0 = buying/keeping wins, 1 = renting/selling wins, 2 = error)")
+	flag.BoolVar(&replMode, "repl", false, "After showing results, accept key=value changes (e.g., monthly_rent=2800) and re-run until 'quit'")
+	flag.BoolVar(&showYearlyDelta, "yearly-delta", false, "Display the annual change in buying and renting net worth between periods")
+	flag.BoolVar(&monthlyAppreciation, "monthly-appreciation", false, "Compound appreciation monthly at each year's rate / 12, like investment returns, instead of by whole years with a partial-year factor (12% a year grows the value 12.68%)")
+	flag.StringVar(&inflationTiming, "inflation-timing", "anniversary", "When recurring costs inflate: anniversary (default and the behavior before this flag: flat for year one, raised by the past year's rate at the start of each later year) or start (each year's rate already applied from its first month, including year one; every period shifts up by a year of inflation)")
+	flag.IntVar(&buyLaterMonths, "buy-later", 0, "Compare buying today against renting for N months, then buying at the appreciated price (BUY vs RENT only)")
+	flag.BoolVar(&showTornado, "tornado", false, "Display how much the 10-year (or shorter --horizon) RENT - BUY result swings when each major input moves by ±20%")
 	flag.Parse()
+	applyColorSettings()
+
+	if displayUnits != "western" && displayUnits != "indian" {
+		fmt.Printf("Error: invalid --units %q (expected western or indian)\n", displayUnits)
+		os.Exit(exitError)
+	}
+	displayCurrency = strings.ToUpper(strings.TrimSpace(displayCurrency))
+	if _, ok := numberLocales[localeName]; !ok {
+		fmt.Printf("Error: invalid --locale %q (expected en-US, de-DE, or fr-FR)\n", localeName)
+		os.Exit(exitError)
+	}
+	if fxRate <= 0 {
+		fmt.Printf("Error: invalid --fx %v (must be more than 0)\n", fxRate)
+		os.Exit(exitError)
+	}
+	// The two flags only make sense together: there's no built-in rate to fall back on
+	fxSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "fx" {
+			fxSet = true
+		}
+	})
+	if fxSet && displayCurrency == "" {
+		fmt.Println("Error: --fx needs --display-currency (e.g., --display-currency EUR --fx 0.92)")
+		os.Exit(exitError)
+	}
+	if displayCurrency != "" && !fxSet {
+		fmt.Printf("Error: --display-currency %s needs --fx with the exchange rate (e.g., --fx 0.92)\n", displayCurrency)
+		os.Exit(exitError)
+	}
+	if inflationTiming != "anniversary" && inflationTiming != "start" {
+		fmt.Printf("Error: invalid --inflation-timing %q (expected anniversary or start)\n", inflationTiming)
+		os.Exit(exitError)
+	}
+	if buyLaterMonths < 0 {
+		fmt.Printf("Error: invalid --buy-later %d (must be 0 or more months)\n", buyLaterMonths)
+		os.Exit(exitError)
+	}
+	if profileDescription != "" && saveProfileName == "" {
+		fmt.Println("Error: --description needs --save-profile (e.g., --save-profile condo --description \"2BR downtown\")")
+		os.Exit(exitError)
+	}
+	if replMode && readStdin {
+		fmt.Println("Error: --repl can't be combined with --stdin (both read from stdin)")
+		os.Exit(exitError)
+	}
+	if !validThemeName(themeName) {
+		fmt.Printf("Error: invalid --theme %q (expected dark, light, or auto)\n", themeName)
+		os.Exit(exitError)
+	}
+	applyTheme()
+	if !validBorderStyle(borderStyle) {
+		fmt.Printf("Error: invalid --border %q (expected normal, rounded, double, thick, ascii, or none)\n", borderStyle)
+		os.Exit(exitError)
+	}
+	if tableResolution != "monthly" && tableResolution != "quarterly" && tableResolution != "yearly" {
+		fmt.Printf("Error: invalid --resolution %q (expected monthly, quarterly, or yearly)\n", tableResolution)
+		os.Exit(exitError)
+	}
+	if precision < 0 || precision > 6 {
+		fmt.Printf("Error: invalid --precision %d (expected 0-6)\n", precision)
+		os.Exit(exitError)
+	}
+	if wrapWidthFlag != 0 && (wrapWidthFlag < minWrapWidth || wrapWidthFlag > maxWrapWidth) {
+		fmt.Printf("Error: invalid --width %d (expected %d-%d)\n", wrapWidthFlag, minWrapWidth, maxWrapWidth)
+		os.Exit(exitError)
+	}
+	if marketDataTTL < 0 {
+		fmt.Printf("Error: invalid --market-ttl %v (must be 0 or more)\n", marketDataTTL)
+		os.Exit(exitError)
+	}
+	if horizonYears < 1 || horizonYears > 100 {
+		fmt.Printf("Error: invalid --horizon %d (expected 1-100 years)\n", horizonYears)
+		os.Exit(exitError)
+	}
+	if periodsSpec != "" {
+		var err error
+		customPeriods, err = parsePeriods(periodsSpec)
+		if err != nil {
+			fmt.Printf("Error: invalid --periods: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
 
-	// Update market data (blocking to ensure we have it for display)
-	marketData, err := updateMarketData()
+	// Saved files live in the user config dir; pick up any left in the current directory
+	resolveConfigPaths()
+	if inputsPath == "" {
+		inputsPath = inputsFile
+	}
+	if saveInputsPath == "" {
+		saveInputsPath = inputsFile
+	}
+	migrateLegacyFiles()
+
+	// Profile management commands run without calculating
+	if listProfilesOnly {
+		if err := displayProfileList(); err != nil {
+			fmt.Println("Error listing profiles:", err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
+	if deleteProfileName != "" {
+		if err := deleteProfile(deleteProfileName); err != nil {
+			fmt.Println("Error deleting profile:", err)
+			os.Exit(exitError)
+		}
+		fmt.Printf("Deleted profile %q.\n", deleteProfileName)
+		return
+	}
+
+	if renameProfileSpec != "" {
+		parts := strings.SplitN(renameProfileSpec, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+			fmt.Println("Error: --rename-profile expects old:new")
+			os.Exit(exitError)
+		}
+		oldName, newName := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if err := renameProfile(oldName, newName, forceOverwrite); err != nil {
+			fmt.Println("Error renaming profile:", err)
+			os.Exit(exitError)
+		}
+		fmt.Printf("Renamed profile %q to %q.\n", oldName, newName)
+		return
+	}
+
+	// Update market data (blocking to ensure we have it for display); --offline only reads the cache
+	var marketData *MarketData
+	var err error
+	if offlineMode {
+		marketData, err = loadMarketData()
+	} else {
+		marketData, err = updateMarketData()
+	}
+	cacheReadable := true
 	if err != nil {
 		fmt.Println("Warning: Could not fetch market data:", err)
-		// Continue anyway with empty market data
-		marketData = &MarketData{
-			VOO: make(map[string]float64),
-			QQQ: make(map[string]float64),
-			VTI: make(map[string]float64),
-			BND: make(map[string]float64),
+		// Fall back to the cached data as it was before the failed fetch
+		marketData, err = loadMarketData()
+		if err != nil {
+			// Continue anyway with empty market data, which must never overwrite the cache
+			fmt.Println("Warning: Could not read cached market data:", err)
+			cacheReadable = false
+			marketData = &MarketData{
+				VOO: make(map[string]float64),
+				QQQ: make(map[string]float64),
+				VTI: make(map[string]float64),
+				BND: make(map[string]float64),
+			}
+		}
+	}
+
+	// Trailing CPI inflation prefills the form's inflation rate
+	if fetchCPI && !offlineMode {
+		cpiInflation, err = fetchCPIInflation()
+		if err != nil {
+			fmt.Println("Warning: Could not fetch CPI inflation:", err)
+		}
+	}
+
+	// Manual return overrides are saved with the cache and reapplied on every run
+	if overrideSpec != "" {
+		if err := addMarketOverrides(marketData, overrideSpec); err != nil {
+			fmt.Printf("Error: invalid --override: %v\n", err)
+			os.Exit(exitError)
+		}
+		if !cacheReadable {
+			fmt.Println("Warning: the market data cache couldn't be read, so --override applies to this run only")
+		} else if err := saveMarketData(marketData); err != nil {
+			fmt.Println("Warning: Could not save --override to the market data cache:", err)
+		}
+	}
+	applyMarketOverrides(marketData)
+
+	if mixSpec != "" {
+		portfolioMix, err = parsePortfolioMix(mixSpec, marketData)
+		if err != nil {
+			fmt.Printf("Error: invalid --mix: %v\n", err)
+			os.Exit(exitError)
 		}
 	}
 
@@ -99,13 +379,40 @@ func main() {
 	savedDefaults = loadInputs()
 	currentInputs = make(map[string]string)
 
-	// If not using defaults, show interactive form
-	if !useDefaults {
-		// Show interactive form with last saved defaults
+	// If inputs are piped or a profile is named, use those; otherwise show interactive form unless using defaults
+	if readStdin {
+		values, err := readStdinInputs()
+		if err != nil {
+			fmt.Println("Error reading stdin:", err)
+			os.Exit(exitError)
+		}
+		currentInputs = values
+	} else if profileName != "" {
+		values, err := loadProfile(profileName)
+		if err != nil {
+			if os.IsNotExist(err) {
+				fmt.Printf("Error: profile %q not found.\n", profileName)
+			} else {
+				fmt.Printf("Error: could not load profile %q: %v\n", profileName, err)
+			}
+			profiles, _ := listProfiles()
+			if len(profiles) > 0 {
+				fmt.Println("Available profiles:", strings.Join(profiles, ", "))
+			} else {
+				fmt.Println("No saved profiles found. Save one with Ctrl+S in the form.")
+			}
+			os.Exit(exitError)
+		}
+		currentInputs = values
+	} else if !useDefaults {
+		// Show interactive form with last saved defaults (and the CPI inflation rate, if fetched)
+		if cpiInflation != nil {
+			savedDefaults["inflation_rate"] = strconv.FormatFloat(math.Round(cpiInflation.Rate*10)/10, 'f', -1, 64)
+		}
 		values, err := RunInteractiveForm(savedDefaults, marketData)
 		if err != nil {
 			fmt.Println("Form cancelled or error:", err)
-			return
+			os.Exit(exitError)
 		}
 		currentInputs = values
 
@@ -115,12 +422,30 @@ func main() {
 		// Check if we have defaults when --defaults flag is used
 		if len(savedDefaults) == 0 {
 			fmt.Println("Error: --defaults flag used but no saved defaults found. Run without the flag first.")
-			return
+			os.Exit(exitError)
 		}
 		// Use saved defaults
 		currentInputs = savedDefaults
 	}
 
+	// Non-interactive runs only write inputs when --save-inputs is given explicitly
+	if readStdin || profileName != "" || useDefaults {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "save-inputs" {
+				saveInputs(currentInputs)
+			}
+		})
+	}
+
+	// Save this run's inputs to a named profile if requested
+	if saveProfileName != "" {
+		if err := saveProfile(saveProfileName, currentInputs, profileDescription); err != nil {
+			fmt.Printf("Warning: could not save profile %q: %v\n", saveProfileName, err)
+		} else {
+			fmt.Printf("Saved inputs to profile %q.\n", saveProfileName)
+		}
+	}
+
 	// Determine which scenario is selected
 	scenarioSellVsKeep, _ := getFloatValue("scenario_sell_vs_keep")
 	isSellVsKeep := scenarioSellVsKeep > 0
@@ -129,7 +454,21 @@ func main() {
 	err = parseConfig(isSellVsKeep)
 	if err != nil {
 		fmt.Println("Error parsing inputs:", err)
-		return
+		os.Exit(exitError)
+	}
+
+	// Flag suspicious (but valid) inputs before showing results
+	if !quietOutput {
+		displayWarnings(validateConfig(isSellVsKeep))
+	}
+
+	// Map projection years to historical returns for backtesting
+	if backtestStartYear != 0 {
+		backtestReturns, err = buildBacktestReturns(marketData, historyTicker, backtestStartYear)
+		if err != nil {
+			fmt.Println("Error setting up backtest:", err)
+			os.Exit(exitError)
+		}
 	}
 
 	// Route to the appropriate scenario
@@ -138,200 +477,684 @@ func main() {
 	} else {
 		runBuyVsRentScenario(marketData)
 	}
+
+	if htmlReportPath != "" {
+		verdict, _ := verdictLine(isSellVsKeep)
+		if err := writeHTMLReport(htmlReportPath, isSellVsKeep, verdict); err != nil {
+			fmt.Println("Error writing HTML report:", err)
+		} else {
+			fmt.Printf("Wrote HTML report to %s\n", htmlReportPath)
+		}
+	}
+
+	if cashflowCSVPath != "" {
+		if err := writeCashflowCSV(cashflowCSVPath); err != nil {
+			fmt.Println("Error writing cash-flow CSV:", err)
+		} else {
+			fmt.Printf("Wrote monthly cash-flow schedule to %s\n", cashflowCSVPath)
+		}
+	}
+
+	if copyToClipboard {
+		copyComparisonToClipboard()
+	}
+
+	if takeSnapshot || diffLastSnapshot {
+		snapshot := buildSnapshot(isSellVsKeep)
+		// Diff before saving so the comparison is against the previous run, not this one
+		if diffLastSnapshot {
+			displaySnapshotDiff(snapshot)
+		}
+		if takeSnapshot {
+			if path, err := saveSnapshot(snapshot); err != nil {
+				fmt.Println("Error saving snapshot:", err)
+			} else {
+				fmt.Printf("Saved snapshot to %s\n", path)
+			}
+		}
+	}
+
+	// Finish with a one-line verdict; the exit code lets scripts branch on it
+	otherWins := displayVerdict(isSellVsKeep)
+	if replMode {
+		otherWins = runWhatIfLoop(marketData, isSellVsKeep)
+	}
+	if otherWins {
+		os.Exit(1)
+	}
+}
+
+// runWhatIfLoop reads key=value changes, merges them into currentInputs and re-runs the scenario until quit or EOF
+// Returns whether the last run's verdict favored renting (BUY vs RENT) or selling (SELL vs KEEP)
+func runWhatIfLoop(marketData *MarketData, isSellVsKeep bool) bool {
+	known := knownInputKeys()
+	_, otherWins := verdictLine(isSellVsKeep)
+
+	noteStyle := newRenderer().NewStyle().Italic(true).Foreground(MonokaiGrey)
+	fmt.Println()
+	fmt.Println(noteStyle.Render("Type key=value to change an input and re-run (e.g., monthly_rent=2800), or quit to exit."))
+	for {
+		fmt.Print("what-if> ")
+		line, err := reader.ReadString('\n')
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "quit" || trimmed == "exit" || (err != nil && trimmed == "") {
+			return otherWins
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !known[key] {
+			fmt.Printf("Error: expected key=value with a known input key, got %q\n", trimmed)
+			continue
+		}
+
+		previous, hadPrevious := currentInputs[key]
+		currentInputs[key] = strings.TrimSpace(value)
+
+		scenarioSellVsKeep, _ := getFloatValue("scenario_sell_vs_keep")
+		runSellVsKeep := scenarioSellVsKeep > 0
+		config = calc.Inputs{}
+		if err := parseConfig(runSellVsKeep); err != nil {
+			fmt.Println("Error parsing inputs:", err)
+			// Restore the last good value so the next change starts from a valid state
+			if hadPrevious {
+				currentInputs[key] = previous
+			} else {
+				delete(currentInputs, key)
+			}
+			config = calc.Inputs{}
+			parseConfig(isSellVsKeep)
+			continue
+		}
+		isSellVsKeep = runSellVsKeep
+
+		if !quietOutput {
+			displayWarnings(validateConfig(isSellVsKeep))
+		}
+		if isSellVsKeep {
+			runSellVsKeepScenario(marketData)
+		} else {
+			runBuyVsRentScenario(marketData)
+		}
+		otherWins = displayVerdict(isSellVsKeep)
+	}
+}
+
+// verdictMonths returns the horizon the verdict is judged at: 10 years, or the projection horizon if shorter
+func verdictMonths() int {
+	return min(breakEvenHorizonMonths, horizonMonths())
+}
+
+// displayVerdict prints which scenario wins at verdictMonths and by how much
+// Returns true when renting (or selling) wins, which selects exit code 1 (errors exit with exitError)
+func displayVerdict(isSellVsKeep bool) bool {
+	verdict, otherWins := verdictLine(isSellVsKeep)
+	re := newRenderer()
+	verdictStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
+	fmt.Println()
+	fmt.Println(verdictStyle.Render(verdict))
+	return otherWins
+}
+
+// verdictLine returns the verdict sentence and whether renting (or selling) wins
+func verdictLine(isSellVsKeep bool) (string, bool) {
+	months := verdictMonths()
+	winner, loser := "BUYING", "RENTING"
+	var difference float64
+	if isSellVsKeep {
+		winner, loser = "KEEPING", "SELLING"
+		difference = calculateSellNetWorth(months) - calculateKeepNetWorth(months)
+	} else {
+		_, _, buyingNetWorth := calculateNetWorth(months)
+		difference = calculateRentingNetWorth(months) - buyingNetWorth
+	}
+	difference = deflate(difference, months)
+
+	otherWins := difference > 0
+	if otherWins {
+		winner = loser
+	}
+
+	horizon := fmt.Sprintf("%d months", months)
+	if months%12 == 0 {
+		horizon = fmt.Sprintf("%d years", months/12)
+	}
+	if months == 12 {
+		horizon = "1 year"
+	}
+
+	return fmt.Sprintf("At %s, %s wins by %s", horizon, winner, formatCurrency(math.Abs(difference))), otherWins
+}
+
+// validateConfig returns advisory warnings for inputs that parse fine but look like mistakes
+// (e.g. rent entered as annual instead of monthly, or rates entered as decimals)
+func validateConfig(isSellVsKeep bool) []string {
+	var warnings []string
+	for _, rate := range config.AppreciationRates {
+		if rate > 15 {
+			warnings = append(warnings, fmt.Sprintf("Appreciation rate of %.1f%%/yr is unusually high", rate))
+			break
+		}
+	}
+	for _, rate := range config.InvestmentReturnRates {
+		if rate > 20 {
+			warnings = append(warnings, fmt.Sprintf("Investment return rate of %.1f%%/yr is unusually high", rate))
+			break
+		}
+	}
+	for _, rate := range config.InflationRates {
+		if rate > 10 {
+			warnings = append(warnings, fmt.Sprintf("Inflation rate of %.1f%%/yr is unusually high", rate))
+			break
+		}
+	}
+	if config.LoanAmount > 0 && config.AnnualRate == 0 {
+		warnings = append(warnings, "Loan rate is 0% with a nonzero loan")
+	}
+
+	if isSellVsKeep {
+		if config.Downpayment < 0 {
+			warnings = append(warnings, fmt.Sprintf("Negative equity: the remaining loan of %s exceeds the current market value of %s",
+				formatCurrency(config.LoanAmount+config.SecondLoanAmount), formatCurrency(config.CurrentMarketValue)))
+		}
+		if config.IncludeRentalIncome && config.MonthlyRentalIncome > 0.02*config.CurrentMarketValue {
+			warnings = append(warnings, fmt.Sprintf("Monthly rental income of %s is over 2%% of the market value - was it entered as annual?",
+				formatCurrency(config.MonthlyRentalIncome)))
+		}
+		return warnings
+	}
+
+	if config.MonthlyRent > 0 && config.MonthlyRent < 0.003*config.PurchasePrice {
+		warnings = append(warnings, fmt.Sprintf("Monthly rent of %s is below 0.3%% of the purchase price", formatCurrency(config.MonthlyRent)))
+	}
+	if config.MonthlyRent > 0.02*config.PurchasePrice {
+		warnings = append(warnings, fmt.Sprintf("Monthly rent of %s is over 2%% of the purchase price - was it entered as annual?",
+			formatCurrency(config.MonthlyRent)))
+	}
+	return warnings
+}
+
+// displayWarnings prints the advisory warnings from validateConfig, if any
+func displayWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	re := newRenderer()
+	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
+	warningStyle := re.NewStyle().Foreground(MonokaiOrange)
+
+	fmt.Println()
+	fmt.Println(titleStyle.Render("WARNINGS"))
+	for _, warning := range warnings {
+		fmt.Println(warningStyle.Render("  ! " + warning))
+	}
 }
 
 // parseConfig parses all input fields into the global config struct
 func parseConfig(isSellVsKeep bool) error {
 	var err error
 
+	// Projection settings from flags, so calculations read everything from config
+	config.HorizonMonths = horizonMonths()
+	config.MonthlyAppreciation = monthlyAppreciation
+	config.InflationAtStart = inflationTiming == "start"
+	config.SellVsKeep = isSellVsKeep
+
 	// === COMMON FIELDS (always parsed) ===
 
-	// Economic assumptions
-	config.inflationRate, err = getFloatValue("inflation_rate")
+	// Economic assumptions (comma-separated inflation rates vary by year, like appreciation)
+	config.InflationRates, err = calc.ParseAppreciationRates(currentInputs["inflation_rate"])
 	if err != nil {
 		return fmt.Errorf("invalid inflation rate: %v", err)
 	}
+	config.InflationRate = config.InflationRates[0]
 
-	config.include30Year, err = getFloatValue("include_30year")
-	if err != nil {
-		config.include30Year = 0 // Default to 10-year projections only
-	}
+	config.Include30Year = getToggleValue("include_30year")
 
 	// Ongoing costs (shared across scenarios)
-	config.annualInsurance, err = getFloatValue("annual_insurance")
+	config.AnnualInsurance, err = getFloatValue("annual_insurance")
 	if err != nil {
 		return fmt.Errorf("invalid annual insurance: %v", err)
 	}
 
-	config.annualTaxes, err = getFloatValue("annual_taxes")
+	config.AnnualTaxes, err = getFloatValue("annual_taxes")
 	if err != nil {
 		return fmt.Errorf("invalid annual taxes: %v", err)
 	}
 
-	config.monthlyExpenses, err = getFloatValue("monthly_expenses")
+	config.MonthlyExpenses, err = getFloatValue("monthly_expenses")
 	if err != nil {
 		return fmt.Errorf("invalid monthly expenses: %v", err)
 	}
 
+	config.UtilitiesDiff, err = getFloatValue("utilities_diff")
+	if err != nil {
+		return fmt.Errorf("invalid utilities difference: %v", err)
+	}
+
+	config.ValueBasedEscrow = getToggleValue("value_based_escrow")
+
+	reassessYears, err := getFloatValue("reassess_every_years")
+	if err != nil || reassessYears < 0 {
+		return fmt.Errorf("invalid reassessment interval - must be 0 or more years")
+	}
+	config.ReassessYears = int(math.Round(reassessYears))
+	if config.ReassessYears > 0 && config.ValueBasedEscrow {
+		return fmt.Errorf("reassess_every_years can't be combined with value-based tax & insurance")
+	}
+	config.ReassessCapPct = 2 // Prop-13-style cap unless set
+	if currentInputs["reassess_cap"] != "" {
+		config.ReassessCapPct, err = getFloatValue("reassess_cap")
+		if err != nil || config.ReassessCapPct < 0 {
+			return fmt.Errorf("invalid reassessment cap - must be 0 or more")
+		}
+	}
+
+	config.MaintenancePct, err = getFloatValue("maintenance_pct")
+	if err != nil || config.MaintenancePct < 0 {
+		return fmt.Errorf("invalid maintenance percentage - must be 0 or more")
+	}
+
 	// Appreciation rate (shared)
 	appreciationRateStr := currentInputs["appreciation_rate"]
-	appreciationRates, err = parseAppreciationRates(appreciationRateStr)
+	config.AppreciationRates, err = calc.ParseAppreciationRates(appreciationRateStr)
 	if err != nil {
 		return fmt.Errorf("invalid appreciation rate: %v", err)
 	}
 
 	// Rental fields (always parsed)
-	config.rentDeposit, err = getFloatValue("rent_deposit")
+	config.RentDeposit, err = getFloatValue("rent_deposit")
 	if err != nil {
 		return fmt.Errorf("invalid rental deposit: %v", err)
 	}
 
-	config.monthlyRent, err = getFloatValue("monthly_rent")
+	config.RentBrokerFee, err = getFloatValue("rent_broker_fee")
+	if err != nil || config.RentBrokerFee < 0 {
+		return fmt.Errorf("invalid broker fee - must be 0 or more")
+	}
+	config.FirstLastMonth = getToggleValue("first_last_month")
+	config.IncludeRentingSell = getToggleValue("include_renting_sell")
+
+	config.MovingCost, err = getFloatValue("moving_cost")
+	if err != nil || config.MovingCost < 0 {
+		return fmt.Errorf("invalid moving cost - must be 0 or more")
+	}
+	rentMoveYears, err := getFloatValue("rent_move_frequency_years")
+	if err != nil || rentMoveYears < 0 {
+		return fmt.Errorf("invalid rent move frequency - must be 0 or more years")
+	}
+	config.RentMoveMonths = int(math.Round(rentMoveYears * 12))
+
+	config.MonthlyRent, err = getFloatValue("monthly_rent")
 	if err != nil {
 		return fmt.Errorf("invalid monthly rent: %v", err)
 	}
 
-	config.annualRentCosts, err = getFloatValue("annual_rent_costs")
+	config.RentStep, config.RentStepPct, err = parseRentStep(currentInputs["rent_step"])
+	if err != nil {
+		return fmt.Errorf("invalid rent step: %v", err)
+	}
+
+	config.AnnualRentCosts, err = getFloatValue("annual_rent_costs")
 	if err != nil {
 		return fmt.Errorf("invalid annual rent costs: %v", err)
 	}
 
-	config.otherAnnualCosts, err = getFloatValue("other_annual_costs")
+	config.OtherAnnualCosts, err = getFloatValue("other_annual_costs")
 	if err != nil {
 		return fmt.Errorf("invalid other annual costs: %v", err)
 	}
 
-	config.investmentReturnRate, err = getFloatValue("investment_return_rate")
+	// Comma-separated investment returns vary by year (e.g., a de-risking glide path)
+	config.InvestmentReturnRates, err = calc.ParseAppreciationRates(currentInputs["investment_return_rate"])
 	if err != nil {
 		return fmt.Errorf("invalid investment return rate: %v", err)
 	}
+	config.InvestmentReturnRate = config.InvestmentReturnRates[0]
 
-	// Selling parameters (always parsed - used differently in each scenario)
-	config.includeSelling, err = getFloatValue("include_selling")
+	config.InvestmentTaxRate, err = getFloatValue("investment_tax_rate")
+	if err != nil || config.InvestmentTaxRate < 0 || config.InvestmentTaxRate > 100 {
+		return fmt.Errorf("invalid investment tax rate - must be between 0 and 100")
+	}
+
+	config.DiscountRate, err = getFloatValue("discount_rate")
 	if err != nil {
-		config.includeSelling = 0
+		return fmt.Errorf("invalid discount rate: %v", err)
 	}
 
-	config.agentCommission, err = getFloatValue("agent_commission")
+	// Selling parameters (always parsed - used differently in each scenario)
+	config.IncludeSelling = getToggleValue("include_selling")
+
+	config.AgentCommission, err = getFloatValue("agent_commission")
 	if err != nil {
-		config.agentCommission = 0
+		config.AgentCommission = 0
 	}
 
-	config.stagingCosts, err = getFloatValue("staging_costs")
+	config.StagingCosts, err = getFloatValue("staging_costs")
 	if err != nil {
-		config.stagingCosts = 0
+		config.StagingCosts = 0
+	}
+
+	config.TransferTaxPct, err = getFloatValue("transfer_tax_pct")
+	if err != nil || config.TransferTaxPct < 0 {
+		return fmt.Errorf("invalid transfer tax - must be 0 or more")
+	}
+	config.SellerConcessions, err = getFloatValue("seller_concessions")
+	if err != nil || config.SellerConcessions < 0 {
+		return fmt.Errorf("invalid seller concessions - must be 0 or more")
+	}
+
+	config.PrepayPenaltyPct, err = getFloatValue("prepay_penalty_pct")
+	if err != nil || config.PrepayPenaltyPct < 0 {
+		return fmt.Errorf("invalid prepayment penalty - must be 0 or more")
+	}
+	config.PrepayPenaltyYears, err = getFloatValue("prepay_penalty_years")
+	if err != nil || config.PrepayPenaltyYears < 0 {
+		return fmt.Errorf("invalid prepayment penalty years - must be 0 or more")
 	}
 
 	// Parse tax-free limits as comma-separated values (like appreciation rates)
 	taxFreeLimitStr := currentInputs["tax_free_limit"]
-	taxFreeLimits, err = parseAppreciationRates(taxFreeLimitStr)
+	config.TaxFreeLimits, err = calc.ParseAppreciationRates(taxFreeLimitStr)
 	if err != nil {
-		taxFreeLimits = []float64{0}
+		config.TaxFreeLimits = []float64{0}
+	}
+
+	// Primary residence (defaults to yes so inputs saved before this field keep their exemption)
+	config.PrimaryResidence = true
+	if strings.TrimSpace(currentInputs["primary_residence"]) != "" {
+		primaryResidence, err := getFloatValue("primary_residence")
+		if err != nil {
+			return fmt.Errorf("invalid primary residence: %v", err)
+		}
+		config.PrimaryResidence = primaryResidence > 0
+	}
+	// Blank months occupied means a new purchase (BUY vs RENT) or, for a home already owned,
+	// the full 5-year window lived in (SELL vs KEEP), keeping the exemption as before
+	config.MonthsOccupied = 0
+	if isSellVsKeep {
+		config.MonthsOccupied = calc.ExclusionWindowMonths
+	}
+	if strings.TrimSpace(currentInputs["months_occupied"]) != "" {
+		monthsOccupied, err := getFloatValue("months_occupied")
+		if err != nil || monthsOccupied < 0 {
+			return fmt.Errorf("invalid months occupied - must be 0 or more")
+		}
+		config.MonthsOccupied = int(monthsOccupied)
 	}
 
-	config.capitalGainsTax, err = getFloatValue("capital_gains_tax")
+	config.CapitalGainsTax, err = getFloatValue("capital_gains_tax")
 	if err != nil {
-		config.capitalGainsTax = 0
+		config.CapitalGainsTax = 0
+	}
+
+	// Short-term rate defaults to the long-term rate when blank
+	config.ShortTermRate = config.CapitalGainsTax
+	if strings.TrimSpace(currentInputs["short_term_rate"]) != "" {
+		config.ShortTermRate, err = getFloatValue("short_term_rate")
+		if err != nil {
+			return fmt.Errorf("invalid short-term capital gains rate: %v", err)
+		}
 	}
 
 	// === SCENARIO-SPECIFIC FIELDS ===
 
-	config.purchasePrice, err = getFloatValue("purchase_price")
-	if err != nil || config.purchasePrice == 0 {
+	config.PurchasePrice, err = getFloatValue("purchase_price")
+	if err != nil || config.PurchasePrice == 0 {
 		return fmt.Errorf("invalid purchase price - cannot be zero")
 	}
 
-	config.loanAmount, err = getFloatValue("loan_amount")
+	config.LoanAmount, err = getFloatValue("loan_amount")
 	if err != nil {
 		return fmt.Errorf("invalid loan amount: %v", err)
 	}
 
 	if isSellVsKeep {
 		// SELL vs KEEP specific parsing
-		config.currentMarketValue, err = getFloatValue("current_market_value")
-		if err != nil || config.currentMarketValue == 0 {
+		config.CurrentMarketValue, err = getFloatValue("current_market_value")
+		if err != nil || config.CurrentMarketValue == 0 {
 			return fmt.Errorf("invalid current market value - cannot be zero")
 		}
 
-		// For SELL vs KEEP, we calculate remaining loan balance from loan parameters
-		if config.loanAmount > 0 {
-			// Get loan parameters
-			config.annualRate, err = getFloatValue("loan_rate")
+		// Rental income if keeping means becoming a landlord
+		config.IncludeRentalIncome = getToggleValue("include_rental_income")
+		config.MonthlyRentalIncome = 0
+		if config.IncludeRentalIncome {
+			config.MonthlyRentalIncome, err = getFloatValue("monthly_rental_income")
+			if err != nil {
+				return fmt.Errorf("invalid monthly rental income: %v", err)
+			}
+			if config.MonthlyRentalIncome < 0 {
+				return fmt.Errorf("invalid monthly rental income - cannot be negative")
+			}
+
+			config.VacancyRate, err = getFloatValue("vacancy_rate")
+			if err != nil || config.VacancyRate < 0 || config.VacancyRate > 100 {
+				return fmt.Errorf("invalid vacancy rate - must be between 0 and 100")
+			}
+			config.ManagementFeePct, err = getFloatValue("management_fee_pct")
+			if err != nil || config.ManagementFeePct < 0 || config.ManagementFeePct > 100 {
+				return fmt.Errorf("invalid management fee - must be between 0 and 100")
+			}
+			config.DepreciableBasis, err = getFloatValue("depreciable_basis")
+			if err != nil || config.DepreciableBasis < 0 {
+				return fmt.Errorf("invalid depreciable basis - must be 0 or more")
+			}
+			config.RentalIncomeTaxRate, err = getFloatValue("rental_income_tax_rate")
+			if err != nil || config.RentalIncomeTaxRate < 0 || config.RentalIncomeTaxRate > 100 {
+				return fmt.Errorf("invalid rental income tax rate - must be between 0 and 100")
+			}
+			// Recapture is charged on sale, so depreciation's yearly tax savings need a rate to offset it
+			if config.DepreciableBasis > 0 && config.RentalIncomeTaxRate == 0 {
+				return fmt.Errorf("depreciable basis needs a rental income tax rate - depreciation only saves tax against rental income, but is recaptured on sale")
+			}
+		}
+
+		// The loan is entered as originally taken out; Normalize derives today's balance from the remaining term
+		config.OriginalLoanAmount = config.LoanAmount
+		if config.OriginalLoanAmount > 0 {
+			config.AnnualRate, err = getFloatValue("loan_rate")
 			if err != nil {
 				return fmt.Errorf("invalid loan rate: %v", err)
 			}
 
-			// Get original loan term
-			originalLoanMonths, err := getIntValue("loan_term", parseDuration)
+			config.LoanMonths, err = getIntValue("loan_term", calc.ParseDuration)
 			if err != nil {
 				return fmt.Errorf("invalid loan term: %v", err)
 			}
 
-			// Get remaining loan term
-			remainingLoanMonths, err := getIntValue("remaining_loan_term", parseDuration)
+			config.RemainingLoanMonths, err = getIntValue("remaining_loan_term", calc.ParseDuration)
 			if err != nil {
 				return fmt.Errorf("invalid remaining loan term: %v", err)
 			}
-
-			// Calculate monthly rate and payment based on original loan
-			config.monthlyRate = config.annualRate / 100 / 12
-			originalPayment := calculateMonthlyPayment(config.loanAmount, config.monthlyRate, originalLoanMonths)
-
-			// Calculate remaining loan balance by simulating payments up to current point
-			monthsElapsed := originalLoanMonths - remainingLoanMonths
-			remainingBalance := config.loanAmount
-			for i := 0; i < monthsElapsed; i++ {
-				interestPayment := remainingBalance * config.monthlyRate
-				principalPayment := originalPayment - interestPayment
-				remainingBalance -= principalPayment
-			}
-
-			// For projections: use remaining term and recalculate payment on remaining balance
-			config.totalMonths = remainingLoanMonths
-			config.monthlyLoanPayment = calculateMonthlyPayment(remainingBalance, config.monthlyRate, remainingLoanMonths)
-			config.downpayment = config.currentMarketValue - remainingBalance // Current equity
-			config.loanAmount = remainingBalance // Update to remaining balance
 		} else {
 			// No loan - fully paid off
-			config.annualRate = 0
-			config.totalMonths = 0
-			config.monthlyRate = 0
-			config.monthlyLoanPayment = 0
-			config.downpayment = config.currentMarketValue // Full equity
-			config.loanAmount = 0
+			config.AnnualRate = 0
 		}
 	} else {
 		// BUY vs RENT specific parsing
-		config.downpayment = config.purchasePrice - config.loanAmount
+		// A downpayment percentage, when given, takes precedence over the loan amount
+		if strings.TrimSpace(currentInputs["downpayment_pct"]) != "" {
+			downpaymentPct, err := getFloatValue("downpayment_pct")
+			if err != nil {
+				return fmt.Errorf("invalid downpayment percentage: %v", err)
+			}
+			if downpaymentPct < 0 || downpaymentPct > 100 {
+				return fmt.Errorf("invalid downpayment percentage - must be between 0 and 100")
+			}
+			config.LoanAmount = config.PurchasePrice * (1 - downpaymentPct/100)
+		}
+		if config.LoanAmount > config.PurchasePrice {
+			return fmt.Errorf("invalid loan amount - %s exceeds the purchase price of %s", formatCurrency(config.LoanAmount), formatCurrency(config.PurchasePrice))
+		}
+
+		// Without a comparable rent, estimate it from a price-to-rent ratio (explicit rent wins)
+		config.PriceToRent, err = getFloatValue("price_to_rent")
+		if err != nil || config.PriceToRent < 0 {
+			return fmt.Errorf("invalid price-to-rent ratio - must be 0 or more")
+		}
+		config.RentEstimated = strings.TrimSpace(currentInputs["monthly_rent"]) == "" && config.PriceToRent > 0
+		if config.RentEstimated {
+			config.MonthlyRent = config.PurchasePrice / config.PriceToRent / 12
+		}
+
+		config.ClosingCosts, err = getFloatValue("closing_costs")
+		if err != nil {
+			return fmt.Errorf("invalid closing costs: %v", err)
+		}
+		if config.ClosingCosts < 0 {
+			return fmt.Errorf("invalid closing costs - cannot be negative")
+		}
 
-		if config.loanAmount > 0 {
-			config.annualRate, err = getFloatValue("loan_rate")
+		config.LoanPoints, err = getFloatValue("loan_points")
+		if err != nil {
+			return fmt.Errorf("invalid loan points: %v", err)
+		}
+		if config.LoanPoints < 0 {
+			return fmt.Errorf("invalid loan points - cannot be negative")
+		}
+
+		// Mortgage interest and property tax only save tax to the extent itemizing beats the standard deduction
+		config.MarginalTaxRate, err = getFloatValue("marginal_tax_rate")
+		if err != nil || config.MarginalTaxRate < 0 || config.MarginalTaxRate > 100 {
+			return fmt.Errorf("invalid marginal tax rate - must be between 0 and 100")
+		}
+		config.PropertyTax, err = getFloatValue("property_tax")
+		if err != nil || config.PropertyTax < 0 {
+			return fmt.Errorf("invalid property tax - must be 0 or more")
+		}
+		config.StateIncomeTax, err = getFloatValue("state_income_tax")
+		if err != nil || config.StateIncomeTax < 0 {
+			return fmt.Errorf("invalid state income tax - must be 0 or more")
+		}
+		config.SaltCap = 10000 // Federal SALT limit unless set
+		if currentInputs["salt_cap"] != "" {
+			config.SaltCap, err = getFloatValue("salt_cap")
+			if err != nil || config.SaltCap < 0 {
+				return fmt.Errorf("invalid SALT cap - must be 0 or more")
+			}
+		}
+		config.StandardDeduction, err = getFloatValue("standard_deduction")
+		if err != nil || config.StandardDeduction < 0 {
+			return fmt.Errorf("invalid standard deduction - must be 0 or more")
+		}
+		config.OtherItemized, err = getFloatValue("other_itemized")
+		if err != nil || config.OtherItemized < 0 {
+			return fmt.Errorf("invalid other itemized deductions - must be 0 or more")
+		}
+
+		if config.LoanAmount > 0 {
+			config.AnnualRate, err = getFloatValue("loan_rate")
 			if err != nil {
 				return fmt.Errorf("invalid loan rate: %v", err)
 			}
 
-			config.totalMonths, err = getIntValue("loan_term", parseDuration)
+			config.LoanMonths, err = getIntValue("loan_term", calc.ParseDuration)
 			if err != nil {
 				return fmt.Errorf("invalid loan term: %v", err)
 			}
 
-			config.monthlyRate = config.annualRate / 100 / 12
-			config.monthlyLoanPayment = calculateMonthlyPayment(config.loanAmount, config.monthlyRate, config.totalMonths)
+			// A balloon loan pays on the full schedule but the remaining balance comes due at the balloon term
+			config.BalloonMonths = 0
+			if strings.TrimSpace(currentInputs["balloon_term"]) != "" {
+				config.BalloonMonths, err = getIntValue("balloon_term", calc.ParseDuration)
+				if err != nil {
+					return fmt.Errorf("invalid balloon term: %v", err)
+				}
+				if config.BalloonMonths <= 0 || config.BalloonMonths >= config.LoanMonths {
+					return fmt.Errorf("invalid balloon term - must be shorter than the %s loan term", periodLabel(config.LoanMonths))
+				}
+			}
+
+			// A temporary buydown lowers the borrower's payment in the first years; the loan still accrues at the note rate
+			config.BuydownRates = nil
+			if strings.TrimSpace(currentInputs["buydown"]) != "" {
+				config.BuydownRates, err = calc.ParseAppreciationRates(currentInputs["buydown"])
+				if err != nil {
+					return fmt.Errorf("invalid buydown: %v", err)
+				}
+				for _, reduction := range config.BuydownRates {
+					if reduction < 0 || reduction > config.AnnualRate {
+						return fmt.Errorf("invalid buydown - each reduction must be between 0 and the %.2f%% loan rate", config.AnnualRate)
+					}
+				}
+			}
 		} else {
-			config.annualRate = 0
-			config.totalMonths = 0
-			config.monthlyRate = 0
-			config.monthlyLoanPayment = 0
+			config.AnnualRate = 0
+		}
+	}
+
+	// Optional second mortgage or HELOC, amortized on its own schedule
+	// It finances part of the purchase (BUY vs RENT) or is owed against today's equity (SELL vs KEEP)
+	config.SecondLoanAmount, err = getFloatValue("second_loan_amount")
+	if err != nil || config.SecondLoanAmount < 0 {
+		return fmt.Errorf("invalid second loan amount - must be 0 or more")
+	}
+	if config.SecondLoanAmount > 0 {
+		config.SecondLoanRate, err = getFloatValue("second_loan_rate")
+		if err != nil || config.SecondLoanRate < 0 {
+			return fmt.Errorf("invalid second loan rate - must be 0 or more")
+		}
+		config.SecondLoanMonths, err = getIntValue("second_loan_term", calc.ParseDuration)
+		if err != nil || config.SecondLoanMonths <= 0 {
+			return fmt.Errorf("invalid second loan term - must be a duration like 10y")
+		}
+		if !isSellVsKeep && config.LoanAmount+config.SecondLoanAmount > config.PurchasePrice {
+			return fmt.Errorf("invalid second loan amount - both loans together exceed the purchase price of %s", formatCurrency(config.PurchasePrice))
+		}
+	}
+
+	// One-time lump-sum prepayment (optional)
+	config.LumpSumAmount, err = getFloatValue("lump_sum_amount")
+	if err != nil {
+		return fmt.Errorf("invalid lump sum amount: %v", err)
+	}
+	lumpSumMonth, err := getFloatValue("lump_sum_month")
+	if err != nil {
+		return fmt.Errorf("invalid lump sum month: %v", err)
+	}
+	config.LumpSumMonth = int(lumpSumMonth)
+	if config.LumpSumAmount < 0 {
+		return fmt.Errorf("invalid lump sum amount - cannot be negative")
+	}
+	if config.LumpSumAmount > 0 && config.LumpSumMonth <= 0 {
+		return fmt.Errorf("invalid lump sum month - must be 1 or later")
+	}
+	if config.LumpSumAmount > 0 {
+		// The lump sum is paid with a scheduled payment, so it has to fall within the loan term and the horizon
+		loanTermMonths := 0
+		if isSellVsKeep && config.OriginalLoanAmount > 0 {
+			loanTermMonths = config.RemainingLoanMonths
+		} else if !isSellVsKeep && config.LoanAmount > 0 {
+			loanTermMonths = config.LoanMonths
+			if config.BalloonMonths > 0 {
+				loanTermMonths = config.BalloonMonths
+			}
+		}
+		if loanTermMonths > 0 && config.LumpSumMonth > loanTermMonths {
+			return fmt.Errorf("invalid lump sum month - month %d is beyond the %s loan term", config.LumpSumMonth, periodLabel(loanTermMonths))
+		}
+		if config.LumpSumMonth > horizonMonths() {
+			return fmt.Errorf("invalid lump sum month - month %d is beyond the %d-year horizon", config.LumpSumMonth, horizonYears)
 		}
 	}
 
-	// Calculate derived monthly costs
-	totalAnnualExpenses := config.annualInsurance + config.annualTaxes
-	monthlyRecurringExpenses := (totalAnnualExpenses / 12) + config.monthlyExpenses
-	config.totalMonthlyBuyingCost = config.monthlyLoanPayment + monthlyRecurringExpenses
+	// One-off expenses like a new roof (optional month:amount pairs)
+	config.OneTimeCosts, err = parseOneTimeCosts(currentInputs["one_time_costs"])
+	if err != nil {
+		return fmt.Errorf("invalid one-time costs: %v", err)
+	}
+	for month := range config.OneTimeCosts {
+		if month > horizonMonths() {
+			return fmt.Errorf("invalid one-time costs - month %d is beyond the %d-year horizon", month, horizonYears)
+		}
+	}
 
-	monthlyRentingExpenses := (config.annualRentCosts / 12) + (config.otherAnnualCosts / 12)
-	config.totalMonthlyRentingCost = config.monthlyRent + monthlyRentingExpenses
+	// Derive the loan payments, equity and first month's costs
+	calc.Normalize(&config)
 
 	return nil
 }
@@ -340,72 +1163,131 @@ func parseConfig(isSellVsKeep bool) error {
 func runBuyVsRentScenario(marketData *MarketData) {
 	// All configuration is already parsed in config global variable
 
-	// Populate global cost arrays for projections
+	// Project the monthly costs for the current config
 	populateMonthlyCosts()
 
-	// Display input parameters
-	displayInputParameters(marketData)
+	// --quiet skips everything but the comparison table and the verdict
+	if !quietOutput {
+		// Display input parameters
+		displayInputParameters(marketData)
 
-	// Display market data after input parameters
-	displayMarketData(marketData)
+		// Display market data after input parameters
+		displayMarketData(marketData)
 
-	// Display projections
-	displayExpenditureTable()
+		// Display projections
+		displayExpenditureTable()
 
-	if config.loanAmount > 0 {
-		displayAmortizationTable()
-	}
+		if config.LoanAmount > 0 || config.SecondLoanAmount > 0 {
+			displayAmortizationTable()
+			displayLoanSummary()
+			if amortFull {
+				displayFullAmortization()
+			}
+		}
 
-	if config.includeSelling > 0 {
-		displaySaleProceeds()
+		if config.IncludeSelling {
+			displaySaleProceeds()
+		}
 	}
 
 	displayComparisonTable()
+	if showChart {
+		displayNetWorthChart("NET WORTH CHART: BUY VS RENT", []chartSeries{
+			{"Buying NW", '*', func(months int) float64 {
+				_, _, netWorth := calculateNetWorth(months)
+				return netWorth
+			}},
+			{"Renting NW", 'o', calculateRentingNetWorth},
+		})
+	}
+	if showYearlyDelta {
+		displayYearlyDeltaTable()
+	}
+	if !quietOutput {
+		displayBreakEvenRent(verdictMonths())
+	}
+
+	if buyLaterMonths > 0 {
+		displayBuyLater(buyLaterMonths)
+	}
+
+	if sensitivitySpec != "" {
+		displaySensitivityGrid(sensitivitySpec, verdictMonths())
+	}
+
+	if showTornado {
+		displayTornado(verdictMonths())
+	}
+
+	if monteCarloTrials > 0 {
+		displayMonteCarlo(marketData, monteCarloTrials, historyTicker, monteCarloSeed)
+	}
 }
 
 // runSellVsKeepScenario handles the SELL vs KEEP scenario calculations and display
 func runSellVsKeepScenario(marketData *MarketData) {
 	// All configuration is already parsed in config global variable
-	// config.purchasePrice = original purchase price (for capital gains)
-	// config.currentMarketValue = current market value
-	// config.loanAmount = remaining loan balance (calculated in parseConfig)
-	// config.downpayment = current equity (currentMarketValue - loanAmount)
+	// config.PurchasePrice = original purchase price (for capital gains)
+	// config.CurrentMarketValue = current market value
+	// config.LoanAmount = remaining loan balance (derived by calc.Normalize)
+	// config.Downpayment = current equity (CurrentMarketValue - LoanAmount)
 
-	// Populate global cost arrays for KEEP scenario (continuing to own)
+	// Project the monthly costs for KEEP scenario (continuing to own)
 	populateMonthlyCosts()
 
-	// Display input parameters
-	displayInputParametersSellVsKeep(marketData)
+	// --quiet skips everything but the comparison table and the verdict
+	if !quietOutput {
+		// Display input parameters
+		displayInputParametersSellVsKeep(marketData)
 
-	// Display market data
-	displayMarketData(marketData)
+		// Display market data
+		displayMarketData(marketData)
 
-	// Display loan amortization if there's a remaining loan
-	if config.loanAmount > 0 {
-		displayAmortizationTable()
-	}
+		// Display loan amortization if there's a remaining loan
+		if config.LoanAmount > 0 || config.SecondLoanAmount > 0 {
+			displayAmortizationTable()
+			displayLoanSummary()
+			if amortFull {
+				displayFullAmortization()
+			}
+		}
 
-	// Display expense breakdowns
-	includeRenting, _ := getFloatValue("include_renting_sell")
-	if includeRenting > 0 {
-		displaySellExpensesBreakdown()
-	}
-	displayKeepExpensesBreakdown()
+		// Display expense breakdowns
+		if config.IncludeRentingSell {
+			displaySellExpensesBreakdown()
+		}
+		displayKeepExpensesBreakdown()
 
-	// Display sale proceeds analysis at various future periods
-	displaySaleProceeds()
+		// Display sale proceeds analysis at various future periods
+		displaySaleProceeds()
+	}
 
 	// Display SELL vs KEEP comparison
 	displaySellVsKeepComparison()
+	if config.IncludeRentalIncome && !quietOutput {
+		displayRentalMetrics()
+	}
+	if showChart {
+		displayNetWorthChart("NET WORTH CHART: SELL VS KEEP", []chartSeries{
+			{"Sell NW", 'o', calculateSellNetWorth},
+			{"Keep NW", '*', calculateKeepNetWorth},
+		})
+	}
 }
 
 // getFloatValue gets a float value from currentInputs
 func getFloatValue(key string) (float64, error) {
 	input := currentInputs[key]
-	value, err := parseAmount(input)
+	value, err := calc.ParseAmount(input)
 	return value, err
 }
 
+// getToggleValue reports whether a toggle in currentInputs is on; blank or invalid values are off
+func getToggleValue(key string) bool {
+	value, err := getFloatValue(key)
+	return err == nil && value > 0
+}
+
 // getIntValue gets an int value from currentInputs with a parser
 func getIntValue(key string, parser func(string) (int, error)) (int, error) {
 	input := currentInputs[key]
@@ -413,9 +1295,9 @@ func getIntValue(key string, parser func(string) (int, error)) (int, error) {
 	return value, err
 }
 
-// loadInputs loads previously saved inputs from file
+// loadInputs loads previously saved inputs from inputsPath
 func loadInputs() map[string]string {
-	data, err := os.ReadFile(inputsFile)
+	data, err := os.ReadFile(inputsPath)
 	if err != nil {
 		return make(map[string]string)
 	}
@@ -429,80 +1311,119 @@ func loadInputs() map[string]string {
 	return inputs
 }
 
-// saveInputs saves current inputs to file for next run
+// saveInputs saves current inputs to saveInputsPath for next run
 func saveInputs(inputs map[string]string) {
 	data, err := json.Marshal(inputs)
 	if err != nil {
 		return
 	}
 
-	os.WriteFile(inputsFile, data, 0644)
+	os.WriteFile(saveInputsPath, data, 0644)
 }
 
-// parseAmount parses currency amounts with k, M, B suffixes
-// Returns 0 for empty input
-// Also handles % sign (strips it out)
-func parseAmount(input string) (float64, error) {
-	input = strings.ToLower(strings.TrimSpace(input))
-
-	// Handle empty input - default to 0
-	if input == "" {
-		return 0, nil
-	}
-
-	// Remove % sign if present (for percentage inputs like "-10%")
-	input = strings.TrimSuffix(input, "%")
-	input = strings.TrimSpace(input)
+// readStdinInputs reads key=value lines from stdin into an inputs map
+// Blank lines and lines starting with # are ignored; unknown keys produce a warning
+func readStdinInputs() (map[string]string, error) {
+	known := knownInputKeys()
+	inputs := make(map[string]string)
 
-	// Check for suffix
-	multiplier := 1.0
-	numStr := input
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
 
-	if strings.HasSuffix(input, "k") {
-		multiplier = 1000.0
-		numStr = strings.TrimSuffix(input, "k")
-	} else if strings.HasSuffix(input, "m") {
-		multiplier = 1000000.0
-		numStr = strings.TrimSuffix(input, "m")
-	} else if strings.HasSuffix(input, "b") {
-		multiplier = 1000000000.0
-		numStr = strings.TrimSuffix(input, "b")
-	}
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			key, value, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value, got %q", lineNum, trimmed)
+			}
+			key = strings.TrimSpace(key)
+			if !known[key] {
+				fmt.Printf("Warning: unknown input key %q on line %d\n", key, lineNum)
+			}
+			inputs[key] = strings.TrimSpace(value)
+		}
 
-	// Parse the numeric part
-	value, err := strconv.ParseFloat(strings.TrimSpace(numStr), 64)
-	if err != nil {
-		return 0, err
+		if err == io.EOF {
+			break
+		}
 	}
 
-	return value * multiplier, nil
+	return inputs, nil
 }
 
-// parseAppreciationRates parses comma-separated appreciation rates
-// Returns array where each entry corresponds to a year, with the last entry applying to all future years
-func parseAppreciationRates(input string) ([]float64, error) {
+// parseOneTimeCosts parses comma-separated month:amount pairs (e.g., "36:15K,120:8000")
+// Months are 1-based; amounts for the same month add up, and quoted amounts may use thousands separators (36:"15,000")
+func parseOneTimeCosts(input string) (map[int]float64, error) {
+	costs := make(map[int]float64)
 	input = strings.TrimSpace(input)
 	if input == "" {
-		return []float64{0}, nil
+		return costs, nil
 	}
 
-	// Split by comma
-	parts := strings.Split(input, ",")
-	rates := make([]float64, 0, len(parts))
-
+	parts, err := calc.SplitList(input)
+	if err != nil {
+		return nil, err
+	}
 	for _, part := range parts {
-		rate, err := parseAmount(part)
+		pieces := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("'%s' is not month:amount", strings.TrimSpace(part))
+		}
+		month, err := strconv.Atoi(strings.TrimSpace(pieces[0]))
+		if err != nil || month < 1 {
+			return nil, fmt.Errorf("invalid month '%s' - must be 1 or later", strings.TrimSpace(pieces[0]))
+		}
+		amount, err := calc.ParseAmount(pieces[1])
 		if err != nil {
-			return nil, fmt.Errorf("invalid rate '%s': %v", strings.TrimSpace(part), err)
+			return nil, fmt.Errorf("invalid amount '%s': %v", strings.TrimSpace(pieces[1]), err)
 		}
-		rates = append(rates, rate)
+		costs[month] += amount
+	}
+	return costs, nil
+}
+
+// formatOneTimeCosts formats one-time costs in month order (e.g., "15.0K in month 36, 8.0K in month 120")
+func formatOneTimeCosts(costs map[int]float64) string {
+	months := make([]int, 0, len(costs))
+	for month := range costs {
+		months = append(months, month)
+	}
+	sort.Ints(months)
+
+	parts := make([]string, len(months))
+	for i, month := range months {
+		parts[i] = fmt.Sprintf("%s in month %d", formatCurrency(costs[month]), month)
 	}
+	return strings.Join(parts, ", ")
+}
 
-	if len(rates) == 0 {
-		return []float64{0}, nil
+// parseRentStep parses a lease-renewal rent increase: dollars (e.g., "50") or a percent with a trailing % (e.g., "5%")
+// A blank input means no step, so rent inflates like other costs
+func parseRentStep(input string) (float64, bool, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return 0, false, nil
 	}
+	isPct := strings.HasSuffix(input, "%")
+	step, err := calc.ParseAmount(strings.TrimSpace(strings.TrimSuffix(input, "%")))
+	if err != nil {
+		return 0, false, err
+	}
+	return step, isPct, nil
+}
 
-	return rates, nil
+// formatRentStep describes the rent step, e.g. "+50.0/month" or "+5.00%"
+func formatRentStep() string {
+	if config.RentStepPct {
+		return fmt.Sprintf("%+.2f%%", config.RentStep)
+	}
+	if config.RentStep < 0 {
+		return formatCurrency(config.RentStep) + "/month"
+	}
+	return "+" + formatCurrency(config.RentStep) + "/month"
 }
 
 // getStringInputAndParse prompts the user and applies a parser function
@@ -514,7 +1435,25 @@ func getStringInputAndParse(prompt string, parser func(string) (int, error)) (in
 
 // displayTable displays a formatted table with title and optional notes
 func displayTable(title string, rows [][]string, notes string, highlightLastRow bool) {
-	re := lipgloss.NewRenderer(os.Stdout)
+	displayStyledTable(title, rows, notes, highlightLastRow, nil)
+}
+
+// displayStyledTable displays a table like displayTable, letting cellStyle adjust individual cell styles
+func displayStyledTable(title string, rows [][]string, notes string, highlightLastRow bool, cellStyle func(row, col int, style lipgloss.Style) lipgloss.Style) {
+	rows, columns := dropZeroColumns(rows)
+	if htmlReportPath != "" {
+		recordReportSection(title, rows, notes, highlightLastRow)
+	}
+	if markdownOutput {
+		displayMarkdownTable(title, rows, notes)
+		return
+	}
+	if plainTables() {
+		displayPlainTable(title, rows, notes)
+		return
+	}
+
+	re := newRenderer()
 
 	// Title style
 	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
@@ -528,8 +1467,7 @@ func displayTable(title string, rows [][]string, notes string, highlightLastRow
 	fmt.Println(titleStyle.Render(title))
 
 	// Create table
-	t := table.New().
-		Border(lipgloss.NormalBorder()).
+	t := applyTableBorder(table.New()).
 		BorderStyle(re.NewStyle().Foreground(MonokaiBorder)).
 		Rows(rows...).
 		StyleFunc(func(row, col int) lipgloss.Style {
@@ -546,6 +1484,10 @@ func displayTable(title string, rows [][]string, notes string, highlightLastRow
 				style = style.Align(lipgloss.Right)
 			}
 
+			if cellStyle != nil {
+				style = cellStyle(row, columns[col], style)
+			}
+
 			return style
 		})
 
@@ -553,117 +1495,332 @@ func displayTable(title string, rows [][]string, notes string, highlightLastRow
 
 	// Print notes if provided
 	if notes != "" {
-		noteStyle := re.NewStyle().Width(100).Italic(true).Foreground(MonokaiGrey).PaddingLeft(2)
+		noteStyle := re.NewStyle().Width(wrapWidth()).Italic(true).Foreground(MonokaiGrey).PaddingLeft(2)
 		fmt.Println(noteStyle.Render(notes))
 	}
 }
 
-// formatCurrency formats a number as currency with K/M suffixes (compact) or full format
+const (
+	defaultWrapWidth = 100
+	minWrapWidth     = 40
+	maxWrapWidth     = 160
+)
+
+// dropZeroColumns removes the columns whose data cells are all zero when --hide-zeros is set
+// The first (label) column is always kept; also returns the original index of each kept column
+func dropZeroColumns(rows [][]string) ([][]string, []int) {
+	var columns []int
+	if len(rows) == 0 {
+		return rows, columns
+	}
+	for col := range rows[0] {
+		if col == 0 || !hideZeros || !zeroColumn(rows, col) {
+			columns = append(columns, col)
+		}
+	}
+	if len(columns) == len(rows[0]) {
+		return rows, columns
+	}
+
+	kept := make([][]string, len(rows))
+	for i, row := range rows {
+		for _, col := range columns {
+			if col < len(row) {
+				kept[i] = append(kept[i], row[col])
+			}
+		}
+	}
+	return kept, columns
+}
+
+// zeroColumn reports whether every data cell (below the header) in the column formats a zero amount
+func zeroColumn(rows [][]string, col int) bool {
+	if len(rows) < 2 {
+		return false
+	}
+	for _, row := range rows[1:] {
+		if col < len(row) && !zeroCell(row[col]) {
+			return false
+		}
+	}
+	return true
+}
+
+// zeroCell reports whether a formatted cell is zero or empty, e.g. "0.0", "$0.00", "(0.0)", "0.0%" or "-"
+func zeroCell(cell string) bool {
+	cell = strings.ReplaceAll(cell, currencySymbol(), "")
+	for _, r := range cell {
+		if !strings.ContainsRune("0.,%-+()", r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// wrapWidth returns the column at which notes and help text wrap: --width if set, otherwise the terminal width
+// clamped to minWrapWidth-maxWrapWidth, or defaultWrapWidth when stdout isn't a terminal
+func wrapWidth() int {
+	if wrapWidthFlag > 0 {
+		return wrapWidthFlag
+	}
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width <= 0 {
+		return defaultWrapWidth
+	}
+	return max(minWrapWidth, min(width, maxWrapWidth))
+}
+
+// displayPlainTable prints a table as tab-separated rows with no borders or color
+func displayPlainTable(title string, rows [][]string, notes string) {
+	fmt.Println()
+	fmt.Println(title)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, "\t"))
+	}
+	if notes != "" {
+		fmt.Println(notes)
+	}
+}
+
+// displayMarkdownTable prints a table as a GitHub-flavored Markdown table under a ## heading
+// Number columns are right-aligned and notes become an italic blockquote
+func displayMarkdownTable(title string, rows [][]string, notes string) {
+	fmt.Println()
+	fmt.Println("## " + title)
+	fmt.Println()
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = strings.ReplaceAll(strings.TrimSpace(cell), "|", "\\|")
+		}
+		fmt.Println("| " + strings.Join(cells, " | ") + " |")
+		if i == 0 {
+			separators := make([]string, len(row))
+			for j := range separators {
+				separators[j] = "---"
+				if j > 0 {
+					separators[j] = "---:"
+				}
+			}
+			fmt.Println("| " + strings.Join(separators, " | ") + " |")
+		}
+	}
+	if notes != "" {
+		fmt.Println()
+		for _, line := range strings.Split(notes, "\n") {
+			if strings.TrimSpace(line) == "" {
+				fmt.Println(">")
+				continue
+			}
+			fmt.Println("> *" + strings.TrimSpace(line) + "*")
+		}
+	}
+}
+
+// formatCurrency formats a number as currency with K/M/B/T suffixes (compact) or full format
 func formatCurrency(amount float64) string {
+	// Convert to the display currency; calculations stay in the input currency
+	amount *= fxRate
+
 	// Handle negative numbers
-	sign := ""
-	if amount < 0 {
-		sign = "-"
+	negative := amount < 0
+	if negative {
 		amount = -amount
 	}
 
-	// If fullNumbers flag is set, use full format with dollar sign and commas
+	// If fullNumbers flag is set, use full format with the currency symbol and the --locale's grouping
+	// Format with the configured decimal places (automatically rounds)
 	if fullNumbers {
-		// Format with 1 decimal place (automatically rounds)
-		formatted := fmt.Sprintf("%.1f", amount)
-		parts := strings.Split(formatted, ".")
-
-		// Add commas to the integer part
-		intPart := parts[0]
-		var result strings.Builder
-		for i, digit := range intPart {
-			if i > 0 && (len(intPart)-i)%3 == 0 {
-				result.WriteRune(',')
-			}
-			result.WriteRune(digit)
+		return signed(localizeAmount(formatDecimal(amount)), negative)
+	}
+
+	// Indian units: compact format with K/L/Cr suffixes, and LCr (lakh crore) above that like the western T tier
+	if displayUnits == "indian" {
+		var formatted string
+		if amount >= 1000000000000 {
+			formatted = formatDecimal(amount/1000000000000) + "LCr"
+		} else if amount >= 10000000 {
+			formatted = formatDecimal(amount/10000000) + "Cr"
+		} else if amount >= 100000 {
+			formatted = formatDecimal(amount/100000) + "L"
+		} else if amount >= 1000 {
+			formatted = formatDecimal(amount/1000) + "K"
+		} else {
+			formatted = formatDecimal(amount)
 		}
-
-		return fmt.Sprintf("%s$%s.%s", sign, result.String(), parts[1])
+		return signed(compactSymbol()+formatted, negative)
 	}
 
-	// Default: compact format with K/M suffixes, no dollar sign (automatically rounds)
+	// Default: compact format with K/M/B/T suffixes, no dollar sign
 	var formatted string
-	if amount >= 1000000 {
+	if amount >= 1000000000000 {
+		// Trillions
+		formatted = formatDecimal(amount/1000000000000) + "T"
+	} else if amount >= 1000000000 {
+		// Billions
+		formatted = formatDecimal(amount/1000000000) + "B"
+	} else if amount >= 1000000 {
 		// Millions
-		formatted = fmt.Sprintf("%.1fM", amount/1000000)
+		formatted = formatDecimal(amount/1000000) + "M"
 	} else if amount >= 1000 {
 		// Thousands
-		formatted = fmt.Sprintf("%.1fK", amount/1000)
+		formatted = formatDecimal(amount/1000) + "K"
 	} else {
 		// Less than 1000
-		formatted = fmt.Sprintf("%.1f", amount)
+		formatted = formatDecimal(amount)
 	}
 
-	return sign + formatted
+	return signed(compactSymbol()+formatted, negative)
 }
 
-// formatNumber formats an integer with commas
-func formatNumber(num int) string {
-	numStr := strconv.Itoa(num)
-	var result strings.Builder
+// localizeAmount adds the --locale's grouping, decimal mark and currency symbol to a plain non-negative amount like "1234.50"
+func localizeAmount(formatted string) string {
+	locale := numberLocales[localeName]
+	parts := strings.SplitN(formatted, ".", 2)
 
-	for i, digit := range numStr {
-		if i > 0 && (len(numStr)-i)%3 == 0 {
-			result.WriteRune(',')
+	// Add thousands separators to the integer part
+	intPart := parts[0]
+	var result strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			result.WriteString(locale.thousands)
 		}
 		result.WriteRune(digit)
 	}
 
-	return result.String()
+	if len(parts) == 2 {
+		result.WriteString(locale.decimal + parts[1])
+	}
+	if locale.symbolAfter {
+		return result.String() + " " + strings.TrimSpace(currencySymbol())
+	}
+	return currencySymbol() + result.String()
 }
 
-// parseDuration parses duration strings like "5y6m", "30y", "6m"
-func parseDuration(duration string) (int, error) {
-	duration = strings.ToLower(duration)
-	years := 0
-	months := 0
+// currencySymbol returns the symbol for --display-currency, or "$" for the input currency
+// Codes without a known symbol are shown as the code itself, e.g. "SEK 1,000"
+func currencySymbol() string {
+	switch displayCurrency {
+	case "", "USD":
+		return "$"
+	case "EUR":
+		return "€"
+	case "GBP":
+		return "£"
+	case "INR":
+		return "₹"
+	case "JPY", "CNY":
+		return "¥"
+	case "CAD":
+		return "C$"
+	case "AUD":
+		return "A$"
+	}
+	return displayCurrency + " "
+}
 
-	// Find 'y' for years
-	yIndex := strings.Index(duration, "y")
-	if yIndex != -1 {
-		yearStr := duration[:yIndex]
-		var err error
-		years, err = strconv.Atoi(yearStr)
-		if err != nil {
-			return 0, fmt.Errorf("invalid year format")
-		}
-		duration = duration[yIndex+1:]
+// compactSymbol returns the currency symbol for compact amounts, which only carry one when --display-currency is set
+func compactSymbol() string {
+	if displayCurrency == "" {
+		return ""
 	}
+	return currencySymbol()
+}
 
-	// Find 'm' for months
-	mIndex := strings.Index(duration, "m")
-	if mIndex != -1 {
-		monthStr := duration[:mIndex]
-		var err error
-		months, err = strconv.Atoi(monthStr)
-		if err != nil {
-			return 0, fmt.Errorf("invalid month format")
+// formatDecimal formats a value with --precision decimal places, trimming trailing zeros when --trim-zeros is set
+func formatDecimal(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', precision, 64)
+	if trimZeros && strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+	return formatted
+}
+
+// signed marks a formatted amount as negative with a leading minus, or parentheses when --neg-parens is set
+func signed(formatted string, negative bool) string {
+	if !negative {
+		return formatted
+	}
+	if negParens {
+		return "(" + formatted + ")"
+	}
+	return "-" + formatted
+}
+
+// deflate converts a nominal amount at the given month to today's dollars when --real is set
+// Only the display is deflated; calculations stay nominal
+func deflate(amount float64, months int) float64 {
+	if !realDollars {
+		return amount
+	}
+	return amount / calc.InflationFactor(&config, months)
+}
+
+// formatRateSchedule formats year-by-year rates, e.g. "3.00% (all years)" or "6.00% (year 1), 3.00% (year 2+)"
+func formatRateSchedule(rates []float64) string {
+	if len(rates) == 1 {
+		return fmt.Sprintf("%.2f%% (all years)", rates[0])
+	}
+	rateStrs := make([]string, len(rates))
+	for i, rate := range rates {
+		if i == len(rates)-1 {
+			rateStrs[i] = fmt.Sprintf("%.2f%% (year %d+)", rate, i+1)
+		} else {
+			rateStrs[i] = fmt.Sprintf("%.2f%% (year %d)", rate, i+1)
 		}
 	}
+	return strings.Join(rateStrs, ", ")
+}
+
+// realDollarsNote returns the note suffix explaining deflated values when --real is set
+func realDollarsNote() string {
+	if !realDollars {
+		return ""
+	}
+	if len(config.InflationRates) > 1 {
+		return "\n\nValues are in today's dollars (deflated by the year-by-year inflation rates)."
+	}
+	return fmt.Sprintf("\n\nValues are in today's dollars (deflated at %.1f%% annual inflation).", config.InflationRate)
+}
+
+// formatNumber formats an integer with commas
+func formatNumber(num int) string {
+	numStr := strconv.Itoa(num)
+	var result strings.Builder
 
-	totalMonths := years*12 + months
-	if totalMonths <= 0 {
-		return 0, fmt.Errorf("duration must be greater than 0")
+	for i, digit := range numStr {
+		if i > 0 && (len(numStr)-i)%3 == 0 {
+			result.WriteRune(',')
+		}
+		result.WriteRune(digit)
 	}
 
-	return totalMonths, nil
+	return result.String()
 }
 
-// calculateMonthlyPayment calculates the monthly payment using the amortization formula
-// M = P * [r(1+r)^n] / [(1+r)^n - 1]
-func calculateMonthlyPayment(principal, monthlyRate float64, months int) float64 {
-	if monthlyRate == 0 {
-		return principal / float64(months)
+// calculateEffectiveAPR solves for the annual rate at which the monthly loan payments repay the net loan proceeds
+// (loan amount minus points and closing costs). Returns false when there is no loan or no fees
+func calculateEffectiveAPR() (float64, bool) {
+	fees := calc.UpfrontBuyingCosts(&config)
+	if config.LoanAmount <= 0 || config.TotalMonths <= 0 || fees <= 0 {
+		return 0, false
+	}
+	netProceeds := config.LoanAmount - fees
+	if netProceeds <= 0 {
+		return 0, false
 	}
 
-	factor := math.Pow(1+monthlyRate, float64(months))
-	monthlyPayment := principal * (monthlyRate * factor) / (factor - 1)
-	return monthlyPayment
+	// Payment on the net proceeds rises with the rate, so bisect until it matches the actual payment
+	low, high := 0.0, 1.0
+	for iter := 0; iter < 200; iter++ {
+		mid := (low + high) / 2
+		if calc.MonthlyPayment(netProceeds, mid, calc.AmortizationMonths(&config)) < config.MonthlyLoanPayment {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2 * 12 * 100, true
 }
 
 // getPeriods returns the list of time periods to display in tables
@@ -688,7 +1845,7 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 		{" 10y", 120},
 	}
 
-	// Extended periods (only if include30Year is true)
+	// Extended periods (only if include30Year is true or the horizon goes past 30 years)
 	extendedPeriods := []struct {
 		label  string
 		months int
@@ -697,12 +1854,65 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 		{" 20y", 240},
 		{" 30y", 360},
 	}
+	for years := 40; years <= horizonYears; years += 10 {
+		extendedPeriods = append(extendedPeriods, struct {
+			label  string
+			months int
+		}{fmt.Sprintf("%4s", periodLabel(years*12)), years * 12})
+	}
+	if horizonYears > 30 && horizonYears%10 != 0 {
+		extendedPeriods = append(extendedPeriods, struct {
+			label  string
+			months int
+		}{fmt.Sprintf("%4s", periodLabel(horizonMonths())), horizonMonths()})
+	}
 
-	// Build standard periods based on include30Year flag
+	// Build standard periods based on include30Year flag, or from --periods when given
 	standardPeriods := basePeriods
-	if include30Year {
+	if include30Year || horizonYears > 30 {
 		standardPeriods = append(standardPeriods, extendedPeriods...)
 	}
+	if len(customPeriods) > 0 {
+		standardPeriods = nil
+		for _, months := range customPeriods {
+			standardPeriods = append(standardPeriods, struct {
+				label  string
+				months int
+			}{fmt.Sprintf("%4s", periodLabel(months)), months})
+		}
+	}
+
+	// Finer resolution replaces the periods within the early horizon with monthly or quarterly steps
+	if step := resolutionStepMonths(); step > 0 {
+		var finePeriods []struct {
+			label  string
+			months int
+		}
+		for months := step; months <= earlyHorizonMonths; months += step {
+			finePeriods = append(finePeriods, struct {
+				label  string
+				months int
+			}{fmt.Sprintf("%4s", periodLabel(months)), months})
+		}
+		for _, period := range standardPeriods {
+			if period.months > earlyHorizonMonths {
+				finePeriods = append(finePeriods, period)
+			}
+		}
+		standardPeriods = finePeriods
+	}
+
+	// Drop periods beyond the projection horizon
+	var withinHorizon []struct {
+		label  string
+		months int
+	}
+	for _, period := range standardPeriods {
+		if period.months <= horizonMonths() {
+			withinHorizon = append(withinHorizon, period)
+		}
+	}
+	standardPeriods = withinHorizon
 
 	// Build the final list of periods, inserting loan term if needed (only if it's a full year)
 	periods := []struct {
@@ -710,10 +1920,10 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 		months int
 	}{}
 
-	// Only include loan term if it's a full year
+	// Only include loan term if it's a full year within the projection (which extends to cover it)
 	var loanTermLabel string
 	includeLoanTerm := false
-	if loanDuration > 0 && loanDuration%12 == 0 {
+	if loanDuration > 0 && loanDuration%12 == 0 && loanDuration <= calc.ProjectionMonths(&config) {
 		years := loanDuration / 12
 		loanTermLabel = fmt.Sprintf("X %dy", years)
 		includeLoanTerm = true
@@ -753,9 +1963,64 @@ func getPeriods(loanDuration int, include30Year bool) []struct {
 	return periods
 }
 
+// horizonMonths returns the projection horizon in months from --horizon
+func horizonMonths() int {
+	return horizonYears * 12
+}
+
+// earlyHorizonMonths is how far --resolution monthly/quarterly refines the table periods
+const earlyHorizonMonths = 24
+
+// resolutionStepMonths returns the period step for --resolution within the early horizon (0 = yearly)
+func resolutionStepMonths() int {
+	switch tableResolution {
+	case "monthly":
+		return 1
+	case "quarterly":
+		return 3
+	}
+	return 0
+}
+
+// periodLabel formats a period in months as whole years (e.g., "7y") or months (e.g., "18m")
+func periodLabel(months int) string {
+	if months%12 == 0 {
+		return fmt.Sprintf("%dy", months/12)
+	}
+	return fmt.Sprintf("%dm", months)
+}
+
+// parsePeriods parses a comma-separated list of durations (e.g., "3y,7y,12y") into sorted, unique months
+func parsePeriods(input string) ([]int, error) {
+	var periods []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		months, err := calc.ParseDuration(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period %q: %v", part, err)
+		}
+		if months > horizonMonths() {
+			return nil, fmt.Errorf("invalid period %q: cannot exceed the %dy horizon (see --horizon)", part, horizonYears)
+		}
+		if !seen[months] {
+			seen[months] = true
+			periods = append(periods, months)
+		}
+	}
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("at least one period is required")
+	}
+	sort.Ints(periods)
+	return periods, nil
+}
+
 // displayInputParameters displays all input parameters in grouped format
 func displayInputParameters(md *MarketData) {
-	re := lipgloss.NewRenderer(os.Stdout)
+	re := newRenderer()
 	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
 	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
 	groupStyle := re.NewStyle().Foreground(MonokaiOrange).Bold(true)
@@ -765,83 +2030,160 @@ func displayInputParameters(md *MarketData) {
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ECONOMIC ASSUMPTIONS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Inflation Rate"), config.inflationRate)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Investment Return Rate"), config.investmentReturnRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Inflation Rate"), formatRateSchedule(config.InflationRates))
+	if inflationTiming == "start" {
+		fmt.Printf("  %s: Start of year (year-one costs are already inflated, so every period shifts up by a year of inflation)\n", labelStyle.Render("Inflation Timing"))
+	}
+	if displayCurrency != "" {
+		fmt.Printf("  %s: %s at %s per unit of the input currency (calculations stay in the input currency)\n", labelStyle.Render("Display Currency"), displayCurrency, strconv.FormatFloat(fxRate, 'f', -1, 64))
+	}
+	if cpiInflation != nil {
+		fmt.Printf("    %s\n", cpiFootnote(cpiInflation))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Investment Return Rate"), formatRateSchedule(config.InvestmentReturnRates))
+	if config.InvestmentTaxRate > 0 {
+		fmt.Printf("  %s: %.2f%% of investment returns (tax drag)\n", labelStyle.Render("Investment Tax Rate"), config.InvestmentTaxRate)
+	}
 
 	// Display market averages with ticker symbols in cyan
 	if md != nil && len(md.VOO) > 0 {
 		vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(md)
 		if vooAvg > 0 {
 			tickerStyle := re.NewStyle().Foreground(MonokaiCyan)
-			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%\n",
+			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%%s\n",
 				tickerStyle.Render("VOO"), vooAvg,
 				tickerStyle.Render("QQQ"), qqqAvg,
 				tickerStyle.Render("VTI"), vtiAvg,
 				tickerStyle.Render("BND"), bndAvg,
-				tickerStyle.Render("60/40"), mix6040Avg)
+				tickerStyle.Render("60/40"), mix6040Avg, mixAverageText(md, tickerStyle))
 		}
 	}
+	if config.DiscountRate != 0 {
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Discount Rate (NPV)"), config.DiscountRate)
+	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("BUYING"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Asset Purchase Price"), formatCurrency(config.purchasePrice))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Amount"), formatCurrency(config.loanAmount))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Downpayment"), formatCurrency(config.downpayment))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.annualRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Asset Purchase Price"), formatCurrency(config.PurchasePrice))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Amount"), formatCurrency(config.LoanAmount))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Downpayment"), formatCurrency(config.Downpayment))
+	if config.ClosingCosts > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Closing Costs"), formatCurrency(config.ClosingCosts))
+	}
+	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.AnnualRate)
+	if config.LoanPoints > 0 {
+		fmt.Printf("  %s: %.2f%% (%s)\n", labelStyle.Render("Loan Points"), config.LoanPoints, formatCurrency(calc.LoanPointsCost(&config)))
+	}
+	if apr, ok := calculateEffectiveAPR(); ok {
+		fmt.Printf("  %s: %.2f%% (including points and closing costs)\n", labelStyle.Render("Effective APR"), apr)
+	}
+	if config.SecondLoanAmount > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Second Loan"), secondLoanSummary())
+	}
 
 	// Format loan duration
 	loanDurationStr := ""
-	if config.totalMonths%12 == 0 {
-		loanDurationStr = fmt.Sprintf("%dy", config.totalMonths/12)
+	if config.TotalMonths%12 == 0 {
+		loanDurationStr = fmt.Sprintf("%dy", config.TotalMonths/12)
 	} else {
-		loanDurationStr = fmt.Sprintf("%d months", config.totalMonths)
+		loanDurationStr = fmt.Sprintf("%d months", config.TotalMonths)
+	}
+	if config.BalloonAmortMonths > 0 {
+		loanDurationStr += fmt.Sprintf(" balloon (amortized over %s)", periodLabel(config.BalloonAmortMonths))
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Loan Duration"), loanDurationStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.annualInsurance))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.annualTaxes))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.monthlyExpenses))
+	if len(config.BuydownRates) > 0 {
+		reductions := make([]string, len(config.BuydownRates))
+		for i, reduction := range config.BuydownRates {
+			reductions[i] = fmt.Sprintf("-%.2f%% (year %d)", reduction, i+1)
+		}
+		fmt.Printf("  %s: %s, saving %s in payments\n", labelStyle.Render("Rate Buydown"), strings.Join(reductions, ", "), formatCurrency(buydownSavings()))
+	}
+	if config.LumpSumAmount > 0 {
+		fmt.Printf("  %s: %s (month %d)\n", labelStyle.Render("Lump-Sum Prepayment"), formatCurrency(config.LumpSumAmount), config.LumpSumMonth)
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.AnnualInsurance))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.AnnualTaxes))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.MonthlyExpenses))
+	if config.UtilitiesDiff != 0 {
+		fmt.Printf("  %s: %s/month vs renting\n", labelStyle.Render("Utilities Difference"), formatCurrency(config.UtilitiesDiff))
+	}
+	if len(config.OneTimeCosts) > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("One-Time Costs"), formatOneTimeCosts(config.OneTimeCosts))
+	}
+	if config.MaintenancePct > 0 {
+		fmt.Printf("  %s: %.2f%% of asset value per year (%s/mo in year 1)\n", labelStyle.Render("Maintenance"), config.MaintenancePct, formatCurrency(calc.MonthlyMaintenance(&config, 0)))
+	}
+	if config.ValueBasedEscrow {
+		fmt.Printf("  %s: Tax & insurance scale with the asset value (%.2f%% of value)\n", labelStyle.Render("Value-Based Escrow"), config.AnnualInsurance/calc.StartingAssetValue(&config)*100)
+	}
+	if config.ReassessYears > 0 {
+		fmt.Printf("  %s: Every %d years to %.2f%% of the asset value, growing at most %.1f%%/year in between\n", labelStyle.Render("Tax Reassessment"), config.ReassessYears, config.AnnualInsurance/calc.StartingAssetValue(&config)*100, config.ReassessCapPct)
+	}
 
-	// Format appreciation rates
-	appreciationRateStr := ""
-	if len(appreciationRates) == 1 {
-		appreciationRateStr = fmt.Sprintf("%.2f%% (all years)", appreciationRates[0])
-	} else {
-		rateStrs := make([]string, len(appreciationRates))
-		for i, rate := range appreciationRates {
-			if i == len(appreciationRates)-1 {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d+)", rate, i+1)
-			} else {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d)", rate, i+1)
+	if config.MarginalTaxRate > 0 {
+		fmt.Printf("  %s: %.1f%% marginal rate vs a %s standard deduction; itemizing saves %s in year 1, %s over %s\n", labelStyle.Render("Itemized Deductions"),
+			config.MarginalTaxRate, formatCurrency(config.StandardDeduction), formatCurrency(calc.ItemizationBenefit(&config, monthly, 0)), formatCurrency(itemizationSavings(verdictMonths())), periodLabel(verdictMonths()))
+		if salt := config.PropertyTax + config.StateIncomeTax; calc.DeductibleSALT(&config, salt, 1) < salt {
+			taxes := "Property tax"
+			if config.StateIncomeTax > 0 {
+				taxes = "Property and state income tax"
 			}
+			fmt.Printf("  %s: %s deduction limited to %s of %s\n", labelStyle.Render("SALT Cap"), taxes, formatCurrency(config.SaltCap), formatCurrency(salt))
 		}
-		appreciationRateStr = strings.Join(rateStrs, ", ")
 	}
+
+	// Format appreciation rates
+	appreciationRateStr := formatRateSchedule(config.AppreciationRates)
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Appreciation Rate"), appreciationRateStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.totalMonthlyBuyingCost))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.TotalMonthlyBuyingCost))
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("RENTING"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.rentDeposit))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.monthlyRent))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.annualRentCosts))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.otherAnnualCosts))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.totalMonthlyRentingCost))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.RentDeposit))
+	displayUpfrontRentingCosts(labelStyle)
+	if config.RentEstimated {
+		fmt.Printf("  %s: %s (estimated from a price-to-rent ratio of %.1f)\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.MonthlyRent), config.PriceToRent)
+	} else {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.MonthlyRent))
+	}
+	if calc.RentStepped(&config) {
+		fmt.Printf("  %s: %s at each yearly renewal (instead of inflation)\n", labelStyle.Render("Rent Step"), formatRentStep())
+	}
+	if config.MonthlyRent > 0 {
+		fmt.Printf("  %s: %.1f (purchase price / annual rent)\n", labelStyle.Render("Price-to-Rent Ratio"), config.PurchasePrice/(config.MonthlyRent*12))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.AnnualRentCosts))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.OtherAnnualCosts))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost"), formatCurrency(config.TotalMonthlyRentingCost))
+	if config.MovingCost > 0 {
+		fmt.Printf("  %s: %s (buyers move once)\n", labelStyle.Render("Moving Cost"), movingCostSummary())
+	}
 
-	if config.includeSelling > 0 {
+	if config.IncludeSelling {
 		fmt.Println()
 		fmt.Println(groupStyle.Render("SELLING"))
 		fmt.Printf("  %s: Yes\n", labelStyle.Render("Include Selling Analysis"))
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.agentCommission)
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.stagingCosts))
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.AgentCommission)
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.StagingCosts))
+		if config.TransferTaxPct > 0 {
+			fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Transfer Tax"), config.TransferTaxPct)
+		}
+		if config.SellerConcessions > 0 {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Seller Concessions"), formatCurrency(config.SellerConcessions))
+		}
+		if config.PrepayPenaltyPct > 0 {
+			fmt.Printf("  %s: %.2f%% of the loan payoff within %s\n", labelStyle.Render("Prepayment Penalty"), config.PrepayPenaltyPct, formatPenaltyYears())
+		}
 
 		// Format tax-free limits
 		taxFreeLimitStr := ""
-		if len(taxFreeLimits) == 1 {
-			taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(taxFreeLimits[0]))
+		if len(config.TaxFreeLimits) == 1 {
+			taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(config.TaxFreeLimits[0]))
 		} else {
-			limitStrs := make([]string, len(taxFreeLimits))
-			for i, limit := range taxFreeLimits {
-				if i == len(taxFreeLimits)-1 {
+			limitStrs := make([]string, len(config.TaxFreeLimits))
+			for i, limit := range config.TaxFreeLimits {
+				if i == len(config.TaxFreeLimits)-1 {
 					limitStrs[i] = fmt.Sprintf("%s (year %d+)", formatCurrency(limit), i+1)
 				} else {
 					limitStrs[i] = fmt.Sprintf("%s (year %d)", formatCurrency(limit), i+1)
@@ -850,7 +2192,11 @@ func displayInputParameters(md *MarketData) {
 			taxFreeLimitStr = strings.Join(limitStrs, ", ")
 		}
 		fmt.Printf("  %s: %s\n", labelStyle.Render("Tax-Free Gains Limit"), taxFreeLimitStr)
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.capitalGainsTax)
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Primary Residence"), primaryResidenceStr())
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.CapitalGainsTax)
+		if config.ShortTermRate != config.CapitalGainsTax {
+			fmt.Printf("  %s: %.2f%% (sales within %d months)\n", labelStyle.Render("Short-Term Gains Rate"), config.ShortTermRate, calc.ShortTermHoldingMonths)
+		}
 	} else {
 		fmt.Println()
 		fmt.Println(groupStyle.Render("SELLING"))
@@ -860,41 +2206,188 @@ func displayInputParameters(md *MarketData) {
 
 // displayAmortizationTable displays loan amortization details
 func displayAmortizationTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
-		{"Period", "Principal Paid", "Interest Paid", "Loan Balance"},
+		{"Period", "Principal Paid", "Interest Paid", "Loan Balance", "Equity", "LTV %"},
 	}
 
 	// Build each data row
 	for _, period := range periods {
 		monthIndex := period.months - 1
-		if monthIndex >= len(remainingLoanBalance) {
-			monthIndex = len(remainingLoanBalance) - 1
-		}
 
-		principalPaid := cumulativePrincipalPaid[monthIndex]
-		interestPaid := cumulativeInterestPaid[monthIndex]
-		loanBalance := remainingLoanBalance[monthIndex]
+		principalPaid := monthly.PrincipalPaid[monthIndex]
+		interestPaid := monthly.InterestPaid[monthIndex]
+		loanBalance := monthly.LoanBalance[monthIndex]
+
+		// Equity and loan-to-value follow the appreciated asset value, like net worth
+		assetValue := calc.AppreciatedValue(&config, calc.StartingAssetValue(&config), period.months)
 
 		rows = append(rows, []string{
 			"LOAN " + period.label,
 			formatCurrency(principalPaid),
 			formatCurrency(interestPaid),
 			formatCurrency(loanBalance),
+			formatCurrency(assetValue - loanBalance),
+			fmt.Sprintf("%.1f%%", loanBalance/assetValue*100),
 		})
 	}
 
-	notes := "Note: Monthly payment is fixed. Each payment covers interest on remaining balance, with the rest going to principal. Early payments are mostly interest."
+	notes := "Note: Monthly payment is fixed. Each payment covers interest on remaining balance, with the rest going to principal. Early payments are mostly interest. 'Equity' = appreciated asset value - loan balance; 'LTV %' = loan balance / appreciated asset value."
+	if config.SecondLoanAmount > 0 {
+		notes += " Principal, interest and balance combine the first and second loans."
+	}
+	if monthly.BalloonPayment > 0 {
+		notes += fmt.Sprintf(" Payments follow a %s schedule; the %s balance left at the balloon date (X) is paid with that month's payment.",
+			periodLabel(config.BalloonAmortMonths), formatCurrency(monthly.BalloonPayment))
+	}
 	displayTable("LOAN AMORTIZATION DETAILS", rows, notes, false)
 }
 
+// loanScheduleMonths returns how many months of loan payments are scheduled: the loan term (or balloon date),
+// or the second loan's term if that's longer
+func loanScheduleMonths() int {
+	months := config.TotalMonths
+	if config.SecondLoanAmount > 0 {
+		months = max(months, config.SecondLoanMonths)
+	}
+	return min(months, len(monthly.LoanBalance))
+}
+
+// loanPayoffMonths returns the month the loans are fully repaid: the end of their schedule,
+// or earlier when a prepayment clears the balance
+func loanPayoffMonths() int {
+	payoffMonths := loanScheduleMonths()
+
+	for i := 0; i < payoffMonths; i++ {
+		if monthly.LoanBalance[i] < 0.01 {
+			return i + 1
+		}
+	}
+	return payoffMonths
+}
+
+// displayLoanSummary displays lifetime loan totals and the recurring cost of owning over the horizon
+func displayLoanSummary() {
+	payoffMonths := loanPayoffMonths()
+	horizon := min(horizonMonths(), len(monthly.BuyingCosts))
+
+	recurringSpend := 0.0
+	for i := 0; i < horizon; i++ {
+		recurringSpend += monthly.BuyingCosts[i]
+	}
+
+	// The balloon payment is principal too, already counted in PrincipalPaid
+	balloonBalance := monthly.BalloonPayment
+	payoffDate := time.Now().AddDate(0, payoffMonths, 0).Format("Jan 2006")
+	rows := [][]string{
+		{"Item", "Amount"},
+		{"Payoff Date", fmt.Sprintf("%s (%s payments)", payoffDate, formatNumber(payoffMonths))},
+		{"Total Principal", formatCurrency(monthly.PrincipalPaid[payoffMonths-1])},
+		{"Total Interest", formatCurrency(monthly.InterestPaid[payoffMonths-1])},
+		{"Recurring Spend (" + periodLabel(horizon) + ")", formatCurrency(recurringSpend)},
+	}
+
+	notes := fmt.Sprintf("Note: Principal and interest are totals at payoff, with payments counted from this month. 'Recurring Spend' sums every monthly cost of owning over the %s horizon: loan payments, tax & insurance, expenses and maintenance, plus any one-time costs. Amounts are in future dollars.", periodLabel(horizon))
+	if balloonBalance > 0 {
+		notes += fmt.Sprintf(" Total Principal includes the %s balloon payment.", formatCurrency(balloonBalance))
+	} else if config.LumpSumAmount > 0 && payoffMonths < config.TotalMonths {
+		notes += fmt.Sprintf(" The lump-sum prepayment pays off the loan %s early.", periodLabel(config.TotalMonths-payoffMonths))
+	}
+	displayTable("LOAN SUMMARY", rows, notes, false)
+}
+
+// displayFullAmortization displays every month of the loan, or writes it to csvPath if set
+func displayFullAmortization() {
+	months := loanScheduleMonths()
+
+	// Build table rows (header + data)
+	rows := [][]string{
+		{"Month", "Payment", "Principal", "Interest", "Balance"},
+	}
+
+	prevPrincipal := 0.0
+	prevInterest := 0.0
+	for i := 0; i < months; i++ {
+		principal := monthly.PrincipalPaid[i] - prevPrincipal
+		interest := monthly.InterestPaid[i] - prevInterest
+		prevPrincipal = monthly.PrincipalPaid[i]
+		prevInterest = monthly.InterestPaid[i]
+
+		if csvPath != "" {
+			// Raw numbers for spreadsheets
+			rows = append(rows, []string{
+				strconv.Itoa(i + 1),
+				fmt.Sprintf("%.2f", principal+interest),
+				fmt.Sprintf("%.2f", principal),
+				fmt.Sprintf("%.2f", interest),
+				fmt.Sprintf("%.2f", monthly.LoanBalance[i]),
+			})
+			continue
+		}
+
+		rows = append(rows, []string{
+			fmt.Sprintf("LOAN %3dm", i+1),
+			formatCurrency(principal + interest),
+			formatCurrency(principal),
+			formatCurrency(interest),
+			formatCurrency(monthly.LoanBalance[i]),
+		})
+	}
+
+	if csvPath != "" {
+		if err := writeCSV(csvPath, rows); err != nil {
+			fmt.Println("Error writing CSV:", err)
+			return
+		}
+		fmt.Printf("\nFull amortization schedule written to %s\n", csvPath)
+		return
+	}
+
+	notes := "Note: One row per month. 'Payment' = Principal + Interest for that month. 'Balance' = Remaining loan balance after the payment."
+	displayTable("FULL LOAN AMORTIZATION SCHEDULE", rows, notes, false)
+}
+
+// writeCSV writes rows to a CSV file at path
+func writeCSV(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeCashflowCSV writes one row per month of the horizon from the monthly arrays, as raw numbers for spreadsheets
+// The column names are stable so scripts can rely on them
+func writeCashflowCSV(path string) error {
+	rows := [][]string{
+		{"month", "buying_cost", "renting_cost", "keep_investment_value", "keep_net_position", "loan_balance"},
+	}
+	for i := 0; i < horizonMonths(); i++ {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			fmt.Sprintf("%.2f", monthly.BuyingCosts[i]),
+			fmt.Sprintf("%.2f", monthly.RentingCosts[i]),
+			fmt.Sprintf("%.2f", monthly.KeepInvestmentValue[i]),
+			fmt.Sprintf("%.2f", monthly.KeepNetPosition[i]),
+			fmt.Sprintf("%.2f", monthly.LoanBalance[i]),
+		})
+	}
+	return writeCSV(path, rows)
+}
+
 // displaySellExpensesBreakdown displays breakdown of rental expenses for SELL scenario
 func displaySellExpensesBreakdown() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
-	// Pre-calculate annual expenses for each year (0-30 years)
+	// Pre-calculate annual expenses for each year of the horizon
 	type yearlyRentExpenses struct {
 		monthlyRent float64
 		rentCosts   float64
@@ -902,17 +2395,18 @@ func displaySellExpensesBreakdown() {
 	}
 
 	// Calculate expenses for each 12-month period
-	yearlyData := make([]yearlyRentExpenses, 31) // 0-30 years
+	projectionYears := calc.ProjectionMonths(&config) / 12
+	yearlyData := make([]yearlyRentExpenses, projectionYears+1)
 
-	for year := 0; year < 31; year++ {
+	for year := 0; year <= projectionYears; year++ {
 		var ye yearlyRentExpenses
 
 		// Calculate monthly rent for this year (12 months)
-		inflatedMonthlyRent := config.monthlyRent * math.Pow(1+config.inflationRate/100, float64(year))
+		inflatedMonthlyRent := calc.RentForMonth(&config, config.MonthlyRent, year*12)
 		ye.monthlyRent = inflatedMonthlyRent * 12
 
 		// Annual rent costs for this year
-		inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(year))
+		inflatedAnnualCost := config.AnnualRentCosts * calc.CostInflationFactor(&config, year*12)
 		ye.rentCosts = inflatedAnnualCost
 
 		ye.total = ye.monthlyRent + ye.rentCosts
@@ -943,23 +2437,23 @@ func displaySellExpensesBreakdown() {
 		cumulativeMonthlyRent := 0.0
 		for i := 0; i < period.months; i++ {
 			year := i / 12
-			inflatedMonthlyRent := config.monthlyRent * math.Pow(1+config.inflationRate/100, float64(year))
+			inflatedMonthlyRent := calc.RentForMonth(&config, config.MonthlyRent, year*12)
 			cumulativeMonthlyRent += inflatedMonthlyRent
 		}
 
 		cumulativeAnnualRentCosts := 0.0
 		fullYears := period.months / 12
 		for year := 0; year < fullYears; year++ {
-			inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(year))
+			inflatedAnnualCost := config.AnnualRentCosts * calc.CostInflationFactor(&config, year*12)
 			cumulativeAnnualRentCosts += inflatedAnnualCost
 		}
 		if period.months%12 > 0 {
-			inflatedAnnualCost := config.annualRentCosts * math.Pow(1+config.inflationRate/100, float64(fullYears))
+			inflatedAnnualCost := config.AnnualRentCosts * calc.CostInflationFactor(&config, fullYears*12)
 			cumulativeAnnualRentCosts += inflatedAnnualCost * float64(period.months%12) / 12.0
 		}
 
-		// Cumulative total includes deposit at start and recoverable at end
-		cumulativeTotal = config.rentDeposit + cumulativeMonthlyRent + cumulativeAnnualRentCosts - (config.rentDeposit * 0.75)
+		// Cumulative total includes deposit and move-in costs at start, and what's recoverable at the end
+		cumulativeTotal = config.RentDeposit + calc.UpfrontRentingCosts(&config) + cumulativeMonthlyRent + cumulativeAnnualRentCosts - calc.RecoverableRentingCosts(&config)
 
 		rows = append(rows, []string{
 			"SELL " + period.label,
@@ -971,34 +2465,45 @@ func displaySellExpensesBreakdown() {
 	}
 
 	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Monthly Rent' and 'Rent Costs' = Amounts for that year (inflated at %.1f%% annually). 'Total' = Sum for that year. 'Cumulative Total' = Running total including initial deposit (%s) and recoverable deposit (%s at end).",
-		config.inflationRate,
-		formatCurrency(config.rentDeposit),
-		formatCurrency(-config.rentDeposit*0.75))
+		config.InflationRate,
+		formatCurrency(config.RentDeposit),
+		formatCurrency(-config.RentDeposit*0.75))
+	if calc.UpfrontRentingCosts(&config) > 0 {
+		noteText += " " + upfrontRentingNote()
+	}
 
 	displayTable("SELL EXPENSES BREAKDOWN", rows, noteText, false)
 }
 
 // displayKeepExpensesBreakdown displays breakdown of ownership expenses for KEEP scenario
 func displayKeepExpensesBreakdown() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
-	// Pre-calculate annual expenses for each year (0-30 years = 372 months to cover year 30 fully)
-	maxMonths := 372
+	// Pre-calculate annual expenses for each year of the horizon (one extra year to cover the last year fully)
+	maxMonths := calc.ProjectionMonths(&config) + 12
 	type yearlyExpenses struct {
 		loanPayment float64
 		insurance   float64
-		otherCosts  float64 // Other annual costs + monthly expenses
+		otherCosts  float64 // Other annual costs + monthly expenses + utilities difference
+		maintenance float64 // Value-based maintenance
+		income      float64 // Rental income received
 		total       float64
 	}
 
 	// Calculate expenses for each 12-month period
-	yearlyData := make([]yearlyExpenses, 31) // 0-30 years
+	projectionYears := calc.ProjectionMonths(&config) / 12
+	yearlyData := make([]yearlyExpenses, projectionYears+1)
 
-	currentInsurance := config.annualInsurance / 12
-	currentOtherCosts := config.annualTaxes / 12
-	currentMonthlyExp := config.monthlyExpenses
+	currentInsurance := config.AnnualInsurance / 12 * calc.CostInflationFactor(&config, 0)
+	currentOtherCosts := config.AnnualTaxes / 12 * calc.CostInflationFactor(&config, 0)
+	currentMonthlyExp := (config.MonthlyExpenses + config.UtilitiesDiff) * calc.CostInflationFactor(&config, 0)
+	if config.ValueBasedEscrow {
+		currentInsurance = calc.MonthlyValueBasedEscrow(&config, 0)
+	} else if config.ReassessYears > 0 {
+		currentInsurance = calc.MonthlyReassessedTax(&config, 0)
+	}
 
-	for year := 0; year < 31; year++ {
+	for year := 0; year <= projectionYears; year++ {
 		var ye yearlyExpenses
 
 		// Calculate for 12 months of this year
@@ -1009,28 +2514,48 @@ func displayKeepExpensesBreakdown() {
 				break
 			}
 
-			// Loan payment only during loan term
-			if monthIndex < config.totalMonths {
-				ye.loanPayment += config.monthlyLoanPayment
+			// Both loans' payments as projected, so they stop at each loan's payoff
+			if monthIndex < len(monthly.PrincipalPaid) {
+				ye.loanPayment += calc.LoanPaymentInMonth(monthly, monthIndex)
 			}
 
 			// Recurring expenses
 			ye.insurance += currentInsurance
 			ye.otherCosts += currentOtherCosts + currentMonthlyExp
+			ye.maintenance += calc.MonthlyMaintenance(&config, monthIndex)
+			ye.income += calc.KeepRentalIncome(&config, monthIndex)
 		}
 
-		ye.total = ye.loanPayment + ye.insurance + ye.otherCosts
+		ye.total = ye.loanPayment + ye.insurance + ye.otherCosts + ye.maintenance
 		yearlyData[year] = ye
 
-		// Apply inflation for next year
-		currentInsurance *= (1 + config.inflationRate/100)
-		currentOtherCosts *= (1 + config.inflationRate/100)
-		currentMonthlyExp *= (1 + config.inflationRate/100)
+		// Apply inflation (or the asset value, for value-based or reassessed tax & insurance) for next year
+		nextYearInflation := calc.RateForYear(config.InflationRates, year)
+		if inflationTiming == "start" {
+			nextYearInflation = calc.RateForYear(config.InflationRates, year+1)
+		}
+		currentInsurance *= (1 + nextYearInflation/100)
+		if config.ValueBasedEscrow {
+			currentInsurance = calc.MonthlyValueBasedEscrow(&config, (year+1)*12)
+		} else if config.ReassessYears > 0 {
+			currentInsurance = calc.MonthlyReassessedTax(&config, (year+1)*12)
+		}
+		currentOtherCosts *= (1 + nextYearInflation/100)
+		currentMonthlyExp *= (1 + nextYearInflation/100)
 	}
 
 	// Build table rows
+	includeMaintenance := config.MaintenancePct > 0
+	includeIncome := config.IncludeRentalIncome
+	header := []string{"Period", "Loan Payment", "Tax/Insurance", "Other Costs"}
+	if includeMaintenance {
+		header = append(header, "Maintenance")
+	}
+	if includeIncome {
+		header = append(header, "Rental Income")
+	}
 	rows := [][]string{
-		{"Period", "Loan Payment", "Tax/Insurance", "Other Costs", "Cumulative Exp", "Investment Val", "Net Position"},
+		append(header, "Cumulative Exp", "Investment Val", "Net Position"),
 	}
 
 	// Build each data row
@@ -1050,7 +2575,7 @@ func displayKeepExpensesBreakdown() {
 		// Calculate cumulative expenses and get investment values from pre-calculated arrays
 		cumulativeTotal := 0.0
 		for i := 0; i < period.months; i++ {
-			cumulativeTotal += monthlyBuyingCosts[i]
+			cumulativeTotal += monthly.BuyingCosts[i]
 		}
 
 		// Get investment value and net position from pre-calculated arrays
@@ -1058,33 +2583,51 @@ func displayKeepExpensesBreakdown() {
 		if monthIndex < 0 {
 			monthIndex = 0
 		}
-		if monthIndex >= len(monthlyKeepInvestmentValue) {
-			monthIndex = len(monthlyKeepInvestmentValue) - 1
-		}
 
-		investmentValue := monthlyKeepInvestmentValue[monthIndex]
-		netPosition := monthlyKeepNetPosition[monthIndex]
+		investmentValue := monthly.KeepInvestmentValue[monthIndex]
+		netPosition := monthly.KeepNetPosition[monthIndex]
 
-		rows = append(rows, []string{
+		row := []string{
 			"KEEP " + period.label,
 			formatCurrency(ye.loanPayment),
 			formatCurrency(ye.insurance),
 			formatCurrency(ye.otherCosts),
+		}
+		if includeMaintenance {
+			row = append(row, formatCurrency(ye.maintenance))
+		}
+		if includeIncome {
+			row = append(row, formatCurrency(ye.income))
+		}
+		rows = append(rows, append(row,
 			formatCurrency(cumulativeTotal),
 			formatCurrency(investmentValue),
 			formatCurrency(netPosition),
-		})
+		))
 	}
 
-	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Loan Payment' = Loan payments for that year (fixed monthly amount, stops after loan term). 'Tax/Insurance' = Annual tax & insurance (inflated at %.1f%% annually). 'Other Costs' = Other annual costs + monthly expenses (inflated). 'Cumulative Exp' = Running total of raw expenses. 'Investment Val' = Value of invested income (compounded at %.1f%% return). 'Net Position' = Investment value minus real out-of-pocket costs.", config.inflationRate, config.investmentReturnRate)
+	noteText := fmt.Sprintf("Note: Shows annual expenses for the specific year of each period. 'Loan Payment' = Loan payments for that year (fixed monthly amount, stops after loan term). 'Tax/Insurance' = Annual tax & insurance (inflated at %.1f%% annually, or scaled with the asset value when value-based or reassessed). 'Other Costs' = Other annual costs + monthly expenses (inflated). 'Cumulative Exp' = Running total of raw expenses. 'Investment Val' = Value of invested income (compounded at %.1f%% return). 'Net Position' = Investment value minus real out-of-pocket costs.", config.InflationRate, config.InvestmentReturnRate)
+	if includeMaintenance {
+		noteText += fmt.Sprintf(" 'Maintenance' = %.2f%% of the asset value each year, growing with appreciation rather than inflation.", config.MaintenancePct)
+	}
+	if includeIncome {
+		noteText += fmt.Sprintf(" 'Rental Income' = Effective rent received that year (inflated annually); it offsets the monthly costs and any surplus is invested. Effective income today is %s/mo (%s gross less %.1f%% vacancy and a %.1f%% management fee on collected rent).",
+			formatCurrency(calc.EffectiveMonthlyRentalIncome(&config)), formatCurrency(config.MonthlyRentalIncome), config.VacancyRate, config.ManagementFeePct)
+		if config.RentalIncomeTaxRate > 0 {
+			noteText += fmt.Sprintf(" Income tax of %.1f%% on rental income after deductible costs (everything but principal) and depreciation is paid from it as well.", config.RentalIncomeTaxRate)
+		}
+	}
+	if config.UtilitiesDiff != 0 {
+		noteText += fmt.Sprintf(" 'Other Costs' includes a %s/mo utilities difference vs renting (inflated).", formatCurrency(config.UtilitiesDiff))
+	}
 
 	displayTable("KEEP EXPENSES BREAKDOWN", rows, noteText, false)
 }
 
 // displayExpenditureTable displays total expenditure for buying vs renting
-// Uses global monthlyBuyingCosts and monthlyRentingCosts arrays
+// Uses the monthly buying and renting costs of the current projection
 func displayExpenditureTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
@@ -1093,41 +2636,153 @@ func displayExpenditureTable() {
 
 	// Add data rows
 	for _, period := range periods {
-		// Calculate total buying expenditure (downpayment + all monthly costs)
-		buyingExpenditure := config.downpayment
+		// Calculate total buying expenditure (downpayment + upfront costs + all monthly costs)
+		buyingExpenditure := config.Downpayment + calc.UpfrontBuyingCosts(&config)
 		for i := 0; i < period.months; i++ {
-			buyingExpenditure += monthlyBuyingCosts[i]
+			buyingExpenditure += monthly.BuyingCosts[i]
 		}
 
 		// Calculate total renting expenditure (deposit + all monthly costs)
-		rentingExpenditure := config.rentDeposit
+		rentingExpenditure := config.RentDeposit + calc.UpfrontRentingCosts(&config)
 		for i := 0; i < period.months; i++ {
-			rentingExpenditure += monthlyRentingCosts[i]
+			rentingExpenditure += monthly.RentingCosts[i]
 		}
 
 		difference := buyingExpenditure - rentingExpenditure
 
 		rows = append(rows, []string{
 			"EXP " + period.label,
-			formatCurrency(buyingExpenditure),
-			formatCurrency(rentingExpenditure),
-			formatCurrency(difference),
+			formatCurrency(deflate(buyingExpenditure, period.months)),
+			formatCurrency(deflate(rentingExpenditure, period.months)),
+			formatCurrency(deflate(difference, period.months)),
 		})
 	}
 
-	notes := fmt.Sprintf("Note: All recurring costs (insurance, taxes, rent, HOA, etc.) are inflated annually at %.1f%% rate.", config.inflationRate)
+	notes := fmt.Sprintf("Note: All recurring costs (insurance, taxes, rent, HOA, etc.) are inflated annually at %.1f%% rate.", config.InflationRate)
+	if inflationTiming == "start" {
+		notes += " Each year's inflation applies from its first month, including year one."
+	}
+	if config.MarginalTaxRate > 0 {
+		notes += fmt.Sprintf(" Buying costs are net of the tax saved by itemizing mortgage interest and property tax over the standard deduction (%s over %s).", formatCurrency(itemizationSavings(verdictMonths())), periodLabel(verdictMonths()))
+	}
+	if calc.RentStepped(&config) {
+		notes += fmt.Sprintf(" Rent instead steps %s at each yearly renewal.", formatRentStep())
+	}
+	notes += realDollarsNote()
 	displayTable("TOTAL EXPENDITURE COMPARISON", rows, notes, false)
+
+	if len(periods) > 0 {
+		last := periods[len(periods)-1]
+		displayExpenditureBreakdown(strings.TrimSpace(last.label), last.months)
+	}
+}
+
+// displayExpenditureBreakdown splits the total buying expenditure for a period into
+// downpayment, upfront costs, loan principal, loan interest and recurring costs
+func displayExpenditureBreakdown(label string, months int) {
+	totalCosts := 0.0
+	for i := 0; i < months; i++ {
+		totalCosts += monthly.BuyingCosts[i]
+	}
+	principal := monthly.PrincipalPaid[months-1]
+	interest := monthly.InterestPaid[months-1]
+	oneTime := 0.0
+	for month, amount := range config.OneTimeCosts {
+		if month <= months {
+			oneTime += amount
+		}
+	}
+	oneTime += calc.BuyerMovingCost(&config, 0)
+	subsidy := 0.0
+	for i := 0; i < months; i++ {
+		subsidy += calc.BuydownSubsidy(&config, i)
+	}
+	recurring := totalCosts - principal - interest - oneTime + subsidy
+	total := config.Downpayment + calc.UpfrontBuyingCosts(&config) + totalCosts
+
+	rows := [][]string{
+		{"Component", "Amount", "Share"},
+	}
+	addRow := func(component string, amount float64) {
+		share := 0.0
+		if total != 0 {
+			share = amount / total * 100
+		}
+		rows = append(rows, []string{component, formatCurrency(deflate(amount, months)), fmt.Sprintf("%.1f%%", share)})
+	}
+	addRow("Downpayment", config.Downpayment)
+	if calc.UpfrontBuyingCosts(&config) > 0 {
+		addRow("Upfront Costs", calc.UpfrontBuyingCosts(&config))
+	}
+	addRow("Loan Principal", principal)
+	addRow("Loan Interest", interest)
+	if subsidy > 0 {
+		addRow("Buydown Subsidy", -subsidy)
+	}
+	addRow("Recurring Costs", recurring)
+	if oneTime != 0 {
+		addRow("One-Time Costs", oneTime)
+	}
+	addRow("Total", total)
+
+	notes := "Note: Recurring costs include tax, insurance, HOA, maintenance and other expenses paid over the period."
+	notes += realDollarsNote()
+	displayTable("BUYING EXPENDITURE BREAKDOWN ("+label+")", rows, notes, true)
+}
+
+// displayYearlyDeltaTable displays the change in buying and renting net worth between consecutive periods
+// Changes spanning more than a year are averaged per year so every row is an annual delta
+func displayYearlyDeltaTable() {
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
+
+	rows := [][]string{
+		{"Period", "Buying Δ/yr", "Renting Δ/yr", "RENT - BUY Δ/yr"},
+	}
+
+	// Start from the position right after purchase: the downpayment as home equity vs the downpayment and upfront costs invested
+	prevMonths := 0
+	prevBuying := config.Downpayment
+	prevRenting := calculateRentingNetWorth(0)
+
+	for _, period := range periods {
+		_, _, buyingNetWorth := calculateNetWorth(period.months)
+		rentingNetWorth := calculateRentingNetWorth(period.months)
+		buying := deflate(buyingNetWorth, period.months)
+		renting := deflate(rentingNetWorth, period.months)
+
+		years := float64(period.months-prevMonths) / 12
+		buyingDelta := (buying - prevBuying) / years
+		rentingDelta := (renting - prevRenting) / years
+
+		rows = append(rows, []string{
+			"DELTA " + period.label,
+			formatCurrency(buyingDelta),
+			formatCurrency(rentingDelta),
+			formatCurrency(rentingDelta - buyingDelta),
+		})
+
+		prevMonths, prevBuying, prevRenting = period.months, buying, renting
+	}
+
+	notes := "Note: Annual deltas, not cumulative totals. Each row is the change in net worth since the previous period, averaged per year when periods are more than a year apart. "
+	notes += "The first row is measured from the day of purchase (the downpayment as home equity, with closing costs and points spent, vs the downpayment and upfront costs invested). "
+	notes += "'RENT - BUY Δ/yr': Positive values mean renting gained more that year, negative values mean buying gained more."
+	notes += realDollarsNote()
+	displayTable("ANNUAL NET WORTH CHANGE: BUY VS RENT", rows, notes, false)
 }
 
 // displayComparisonTable displays buy vs rent net worth projections side-by-side
-// Uses global monthlyBuyingCosts and monthlyRentingCosts arrays
+// Uses the monthly buying and renting costs of the current projection
 func displayComparisonTable() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
 	// Build table rows (header + data)
 	rows := [][]string{
 		{"Period", "Asset Value", "Buying NW", "Cum Savings", "Market Return", "Renting NW", "RENT - BUY"},
 	}
+	if config.DiscountRate != 0 {
+		rows[0] = append(rows[0], "NPV RENT - BUY")
+	}
 
 	// Build each data row
 	for _, period := range periods {
@@ -1136,143 +2791,217 @@ func displayComparisonTable() {
 		rentingNetWorth := calculateRentingNetWorth(period.months)
 
 		// Calculate cumulative savings (without investment growth)
-		cumulativeSavings := config.downpayment - config.rentDeposit
+		cumulativeSavings := config.Downpayment + calc.UpfrontBuyingCosts(&config) - config.RentDeposit - calc.UpfrontRentingCosts(&config)
 		for i := 0; i < period.months; i++ {
-			cumulativeSavings += monthlyBuyingCosts[i] - monthlyRentingCosts[i]
+			cumulativeSavings += monthly.BuyingCosts[i] - monthly.RentingCosts[i]
 		}
 
 		// Calculate market return (investment growth portion only)
-		recoverableDeposit := config.rentDeposit * 0.75
-		marketReturn := rentingNetWorth - cumulativeSavings - recoverableDeposit
+		marketReturn := rentingNetWorth - cumulativeSavings - calc.RecoverableRentingCosts(&config)
 
 		difference := rentingNetWorth - buyingNetWorth
 
 		rows = append(rows, []string{
 			"NET " + period.label,
-			formatCurrency(assetValue),
-			formatCurrency(buyingNetWorth),
-			formatCurrency(cumulativeSavings),
-			formatCurrency(marketReturn),
-			formatCurrency(rentingNetWorth),
-			formatCurrency(difference),
+			formatCurrency(deflate(assetValue, period.months)),
+			formatCurrency(deflate(buyingNetWorth, period.months)),
+			formatCurrency(deflate(cumulativeSavings, period.months)),
+			formatCurrency(deflate(marketReturn, period.months)),
+			formatCurrency(deflate(rentingNetWorth, period.months)),
+			formatCurrency(deflate(difference, period.months)),
 		})
+
+		if config.DiscountRate != 0 {
+			buyNPV, rentNPV := calculateBuyVsRentNPV(period.months)
+			rows[len(rows)-1] = append(rows[len(rows)-1], formatCurrency(rentNPV-buyNPV))
+		}
 	}
 
 	// Build note text with conditional buying NW explanation
-	noteText := fmt.Sprintf("Note: 'Cum Savings' = Cumulative Savings track raw difference in costs (Buying - Renting) without investment growth. See Total Expenditure Comparison.\n\n'Market Return' = investment growth using monthly dollar-cost averaging at %.0f%% annual rate. Each month's savings are invested immediately and compounded monthly. This models realistic investing behavior (not lump sum at year start), so effective return < annual rate for short periods.\n\n'Renting NW' = Cumul. Savings + Market Return + 75%% recoverable deposit. ", config.investmentReturnRate)
-	if config.includeSelling > 0 {
+	noteText := fmt.Sprintf("Note: 'Cum Savings' = Cumulative Savings track raw difference in costs (Buying - Renting) without investment growth. See Total Expenditure Comparison.\n\n'Market Return' = investment growth using monthly dollar-cost averaging at %.0f%% annual rate. Each month's savings are invested immediately and compounded monthly. This models realistic investing behavior (not lump sum at year start), so effective return < annual rate for short periods.", config.InvestmentReturnRate)
+	if config.InvestmentTaxRate > 0 {
+		noteText += fmt.Sprintf(" Returns are reduced by the %.0f%% investment tax.", config.InvestmentTaxRate)
+	}
+	noteText += "\n\n'Renting NW' = Cumul. Savings + Market Return + 75% recoverable deposit. "
+	if config.IncludeSelling {
 		noteText += "'Buying NW' = Net proceeds after selling (sale price - selling costs - loan payoff - taxes). "
 	} else {
 		noteText += "'Buying NW' = Asset value - remaining loan balance. "
 	}
+	if calc.UpfrontBuyingCosts(&config) > 0 {
+		noteText += fmt.Sprintf("Renting NW starts with the %s of upfront costs (closing costs and loan points) invested alongside the downpayment, since a renter keeps that cash. ", formatCurrency(calc.UpfrontBuyingCosts(&config)))
+	}
+	if config.MovingCost > 0 {
+		noteText += fmt.Sprintf("Moving costs: %s (inflated); buyers move once at purchase. ", movingCostSummary())
+	}
 	noteText += "'RENT - BUY': Positive values mean renting wins, negative values mean buying wins."
+	if len(backtestReturns) > 0 {
+		noteText += fmt.Sprintf("\n\nBacktest: renting investment uses actual %s returns for %d-%d (projection years 1-%d)",
+			strings.ToUpper(historyTicker), backtestStartYear, backtestStartYear+len(backtestReturns)-1, len(backtestReturns))
+		if len(backtestReturns)*12 < len(monthly.BuyingCosts) {
+			noteText += fmt.Sprintf("; history runs out after that, so later years use the flat %.1f%% rate", config.InvestmentReturnRate)
+		}
+		noteText += "."
+	}
+	if config.DiscountRate != 0 {
+		noteText += fmt.Sprintf("\n\n'NPV RENT - BUY' = Present value of renting minus present value of buying, discounting every monthly cash flow and the ending net worth at %.1f%% annually. Positive values mean renting wins.", config.DiscountRate)
+	}
+	noteText += realDollarsNote()
 
 	displayTable("NET WORTH PROJECTIONS: BUY VS RENT", rows, noteText, false)
+	recordClipboardTable("NET WORTH PROJECTIONS: BUY VS RENT", rows, noteText)
 }
 
-// calculateSaleProceeds calculates the net proceeds from selling at a given time
-func calculateSaleProceeds(months int) (salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds float64) {
-	// Determine starting price for appreciation calculation
-	// SELL vs KEEP: start from current market value
-	// BUY vs RENT: start from original purchase price
-	startingPrice := config.purchasePrice
-	if config.currentMarketValue > 0 {
-		startingPrice = config.currentMarketValue
-	}
-
-	// Calculate asset value (sale price) by compounding appreciation rates
-	salePrice = startingPrice
-	years := months / 12
-	remainingMonths := months % 12
-
-	// Apply each year's rate
-	for year := 0; year < years; year++ {
-		rateIndex := year
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
-		}
-		salePrice *= (1 + appreciationRates[rateIndex]/100)
-	}
+// calculateBuyVsRentNPV calculates the net present value of buying and of renting over the given months
+// Upfront outlays and monthly costs are outflows; the ending net worth (sale proceeds or recoverable deposit) is the inflow
+func calculateBuyVsRentNPV(months int) (buyNPV, rentNPV float64) {
+	monthlyDiscountRate := config.DiscountRate / 100 / 12
 
-	// Apply partial year if there are remaining months
-	if remainingMonths > 0 {
-		rateIndex := years
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
-		}
-		partialYearFactor := math.Pow(1+appreciationRates[rateIndex]/100, float64(remainingMonths)/12.0)
-		salePrice *= partialYearFactor
+	buyNPV = -config.Downpayment - calc.UpfrontBuyingCosts(&config)
+	rentNPV = -config.RentDeposit - calc.UpfrontRentingCosts(&config)
+	discountFactor := 1.0
+	for i := 0; i < months; i++ {
+		discountFactor /= (1 + monthlyDiscountRate)
+		buyNPV -= monthly.BuyingCosts[i] * discountFactor
+		rentNPV -= monthly.RentingCosts[i] * discountFactor
 	}
 
-	// Calculate agent commission
-	agentFee := salePrice * (config.agentCommission / 100)
+	_, _, buyingNetWorth := calculateNetWorth(months)
+	buyNPV += buyingNetWorth * discountFactor
+	rentNPV += calc.RecoverableRentingCosts(&config) * discountFactor
 
-	// Combine agent commission and staging costs
-	totalSellingCosts = agentFee + config.stagingCosts
+	return buyNPV, rentNPV
+}
 
-	// Get remaining loan balance
-	monthIndex := months - 1
-	if monthIndex >= len(remainingLoanBalance) {
-		monthIndex = len(remainingLoanBalance) - 1
-	}
-	loanPayoff = remainingLoanBalance[monthIndex]
+// calculateSaleProceeds calculates the net proceeds from selling the current config's asset at a given time
+func calculateSaleProceeds(months int) (salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds float64) {
+	return calc.SaleProceeds(&config, monthly, months)
+}
 
-	// Calculate capital gains (selling costs are deductible)
-	capitalGains = salePrice - config.purchasePrice - totalSellingCosts
+// formatPenaltyYears describes the prepayment penalty window (e.g., "3 years")
+func formatPenaltyYears() string {
+	return strconv.FormatFloat(config.PrepayPenaltyYears, 'f', -1, 64) + " years"
+}
 
-	// Get tax-free limit for this period
-	// For year 1 (12 months), use index 0; for year 2 (24 months), use index 1, etc.
-	taxFreeLimitIndex := years - 1
-	if taxFreeLimitIndex < 0 {
-		taxFreeLimitIndex = 0
-	}
-	if taxFreeLimitIndex >= len(taxFreeLimits) {
-		taxFreeLimitIndex = len(taxFreeLimits) - 1
+// calculateBuyIRR calculates the annualized IRR of owning for the given months and selling for netProceeds
+// Returns false when the cash flows have no sign change (no real IRR)
+func calculateBuyIRR(months int, netProceeds float64) (float64, bool) {
+	// Cash flows: initial equity and upfront costs out at month 0, monthly costs out, sale proceeds in at the end
+	cashFlows := make([]float64, months+1)
+	cashFlows[0] = -config.Downpayment - calc.UpfrontBuyingCosts(&config)
+	for i := 0; i < months; i++ {
+		cashFlows[i+1] = -monthly.BuyingCosts[i]
 	}
-	taxFreeLimit := taxFreeLimits[taxFreeLimitIndex]
+	cashFlows[months] += netProceeds
 
-	// Calculate taxable gains (after exemption)
-	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
+	npv := func(rate float64) float64 {
+		total := 0.0
+		factor := 1.0
+		for _, cf := range cashFlows {
+			total += cf / factor
+			factor *= 1 + rate
+		}
+		return total
+	}
 
-	// Calculate tax on gains
-	taxOnGains = taxableGains * (config.capitalGainsTax / 100)
+	// Bisect the monthly rate between -50% and +100%
+	low, high := -0.5, 1.0
+	npvLow, npvHigh := npv(low), npv(high)
+	if math.IsNaN(npvLow) || math.IsNaN(npvHigh) || (npvLow > 0) == (npvHigh > 0) {
+		return 0, false
+	}
 
-	// Calculate net proceeds
-	netProceeds = salePrice - totalSellingCosts - loanPayoff - taxOnGains
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		npvMid := npv(mid)
+		if (npvMid > 0) == (npvLow > 0) {
+			low, npvLow = mid, npvMid
+		} else {
+			high = mid
+		}
+	}
 
-	return
+	monthlyIRR := (low + high) / 2
+	return (math.Pow(1+monthlyIRR, 12) - 1) * 100, true
 }
 
 // displaySaleProceeds displays the proceeds from selling the property at various periods
 func displaySaleProceeds() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
 	// Build table rows (header + data)
+	showTransferTax := config.TransferTaxPct > 0
+	showConcessions := config.SellerConcessions > 0
+	header := []string{"Period", "Sale Price", "Selling Cost"}
+	if showTransferTax {
+		header = append(header, "Transfer Tax")
+	}
+	if showConcessions {
+		header = append(header, "Concessions")
+	}
+	header = append(header, "Loan Payoff")
+	showPenalty := config.PrepayPenaltyPct > 0
+	if showPenalty {
+		header = append(header, "Prepay Penalty")
+	}
 	rows := [][]string{
-		{"Period", "Sale Price", "Selling Cost", "Loan Payoff", "Cap Gains", "Tax", "Net Proceeds"},
+		append(header, "Cap Gains", "Tax", "Net Proceeds", "IRR"),
 	}
 
 	// Build each data row
 	for _, period := range periods {
 		salePrice, totalSellingCosts, loanPayoff, capitalGains, taxOnGains, netProceeds := calculateSaleProceeds(period.months)
 
-		rows = append(rows, []string{
+		irrStr := "n/a"
+		if irr, ok := calculateBuyIRR(period.months, netProceeds); ok {
+			irrStr = fmt.Sprintf("%.2f%%", irr)
+		}
+
+		row := []string{
 			"SALE " + period.label,
 			formatCurrency(salePrice),
 			formatCurrency(totalSellingCosts),
-			formatCurrency(loanPayoff),
+		}
+		if showTransferTax {
+			row = append(row, formatCurrency(calc.TransferTax(&config, salePrice)))
+		}
+		if showConcessions {
+			row = append(row, formatCurrency(config.SellerConcessions))
+		}
+		row = append(row, formatCurrency(loanPayoff))
+		if showPenalty {
+			row = append(row, formatCurrency(calc.PrepayPenalty(&config, period.months, loanPayoff)))
+		}
+		rows = append(rows, append(row,
 			formatCurrency(capitalGains),
 			formatCurrency(taxOnGains),
 			formatCurrency(netProceeds),
-		})
+			irrStr,
+		))
 	}
 
 	notes := "Note: Appreciation rates are applied year-by-year (compounded). If multiple rates are specified (e.g., '-20,-10,-5'), first rate applies to year 1, second to year 2, etc. The last rate applies to all remaining years. Sale price = compounded property value."
+	if showTransferTax || showConcessions {
+		notes += " 'Selling Cost' includes agent commission, staging, transfer tax and seller concessions; the latter two are also shown separately. All are deductible from capital gains."
+	}
+	if showPenalty {
+		notes += fmt.Sprintf("\n\n'Prepay Penalty' = %.2f%% of the loan payoff for sales within %s, deducted from net proceeds.", config.PrepayPenaltyPct, formatPenaltyYears())
+		if config.SellVsKeep {
+			notes += " For SELL vs KEEP the window counts from today."
+		}
+	}
+	if config.DepreciableBasis > 0 && config.IncludeRentalIncome {
+		notes += fmt.Sprintf("\n\n'Tax' includes %d%% recapture of the depreciation taken while renting the asset out (%s per year for up to 27.5 years); 'Cap Gains' is measured before that basis reduction.", calc.DepreciationRecaptureRate, formatCurrency(calc.MonthlyDepreciation(&config, 0)*12))
+	}
+	if config.ShortTermRate != config.CapitalGainsTax && !config.SellVsKeep {
+		notes += fmt.Sprintf("\n\n'Tax' uses the %.1f%% short-term rate for sales within %d months of purchase and the %.1f%% long-term rate after that.", config.ShortTermRate, calc.ShortTermHoldingMonths, config.CapitalGainsTax)
+	}
+	notes += fmt.Sprintf("\n\n'IRR' = Annualized internal rate of return treating the initial equity and every monthly cost as outflows and net proceeds at sale as the inflow. Compare against the %.1f%% investment return rate. 'n/a' means the cash flows never change sign, so no IRR exists.", config.InvestmentReturnRate)
 	displayTable("SALE PROCEEDS ANALYSIS", rows, notes, false)
 }
 
 // displayNetWorthTable displays net worth projections in a table format
-// Uses global monthlyBuyingCosts array
+// Uses the monthly buying costs of the current projection
 func displayNetWorthTable(purchasePrice, downpayment float64, loanDuration int, includeSelling float64,
 	agentCommission, stagingCosts, taxFreeLimit, capitalGainsTax float64) {
 	// Define standard periods
@@ -1349,192 +3078,175 @@ func displayNetWorthTable(purchasePrice, downpayment float64, loanDuration int,
 	}
 }
 
-// calculateNetWorth calculates the asset value, total expenditure, and net worth for a given time period
-// Uses the global monthlyBuyingCosts and remainingLoanBalance arrays
+// calculateNetWorth calculates the asset value, total expenditure, and net worth of the current config for a given time period
 func calculateNetWorth(months int) (float64, float64, float64) {
-	// Calculate asset value by compounding each year's appreciation rate
-	assetValue := config.purchasePrice
-	years := months / 12
-	remainingMonths := months % 12
+	return calc.NetWorth(&config, monthly, months)
+}
 
-	// Apply each year's rate
-	for year := 0; year < years; year++ {
-		rateIndex := year
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1 // Use last rate for all future years
-		}
-		assetValue *= (1 + appreciationRates[rateIndex]/100)
-	}
+// secondLoanSummary describes the second loan, e.g. "100.0K at 8.50% over 10y (1.2K/month)"
+func secondLoanSummary() string {
+	return fmt.Sprintf("%s at %.2f%% over %s (%s/month)", formatCurrency(config.SecondLoanAmount), config.SecondLoanRate,
+		periodLabel(config.SecondLoanMonths), formatCurrency(config.SecondLoanPayment))
+}
 
-	// Apply partial year if there are remaining months
-	if remainingMonths > 0 {
-		rateIndex := years
-		if rateIndex >= len(appreciationRates) {
-			rateIndex = len(appreciationRates) - 1
-		}
-		partialYearFactor := math.Pow(1+appreciationRates[rateIndex]/100, float64(remainingMonths)/12.0)
-		assetValue *= partialYearFactor
+// buydownSavings returns the total payments covered by the buydown over its years
+func buydownSavings() float64 {
+	total := 0.0
+	for month := 0; month < len(config.BuydownRates)*12; month++ {
+		total += calc.BuydownSubsidy(&config, month)
 	}
+	return total
+}
 
-	// Calculate total expenditure by summing monthly costs from array
-	totalExpenditure := config.downpayment
-	for i := 0; i < months; i++ {
-		totalExpenditure += monthlyBuyingCosts[i]
-	}
+// populateMonthlyCosts projects the current config into monthly
+func populateMonthlyCosts() {
+	monthly = calc.Project(&config)
+}
 
-	// Calculate net worth
-	var netWorth float64
-	if config.includeSelling > 0 {
-		// If selling is enabled, use net proceeds after selling costs
-		_, _, _, _, _, netProceeds := calculateSaleProceeds(months)
-		netWorth = netProceeds
-	} else {
-		// Otherwise, just asset value minus loan balance
-		monthIndex := months - 1
-		if monthIndex >= len(remainingLoanBalance) {
-			monthIndex = len(remainingLoanBalance) - 1
-		}
-		loanBalance := remainingLoanBalance[monthIndex]
-		netWorth = assetValue - loanBalance
+// itemizationSavings returns the total itemization benefit over the given number of months
+func itemizationSavings(months int) float64 {
+	total := 0.0
+	for year := 0; year*12 < months; year++ {
+		total += calc.ItemizationBenefit(&config, monthly, year)
 	}
-
-	return assetValue, totalExpenditure, netWorth
+	return total
 }
 
-// populateMonthlyCosts fills global arrays with monthly costs for buying and renting
-// Uses global config struct for all parameters
-func populateMonthlyCosts() {
-	maxMonths := 360 // 30 years maximum projection
-
-	monthlyBuyingCosts = make([]float64, maxMonths)
-	monthlyRentingCosts = make([]float64, maxMonths)
-	remainingLoanBalance = make([]float64, maxMonths)
-	cumulativePrincipalPaid = make([]float64, maxMonths)
-	cumulativeInterestPaid = make([]float64, maxMonths)
-
-	// Calculate monthly recurring expenses from config
-	totalAnnualExpenses := config.annualInsurance + config.annualTaxes
-	monthlyRecurringExpenses := (totalAnnualExpenses / 12) + config.monthlyExpenses
-
-	// Calculate current rental cost with annual increases
-	currentRentingCost := config.totalMonthlyRentingCost
-
-	// Track current recurring expenses (will increase with inflation)
-	currentRecurringExpenses := monthlyRecurringExpenses
-
-	// Track remaining loan balance
-	currentBalance := config.loanAmount
-	totalPrincipalPaid := 0.0
-	totalInterestPaid := 0.0
-
-	for i := 0; i < maxMonths; i++ {
-		// Apply inflation to all costs at the start of each year (except the first month)
-		if i > 0 && i%12 == 0 {
-			currentRentingCost *= (1 + config.inflationRate/100)
-			currentRecurringExpenses *= (1 + config.inflationRate/100)
-		}
-
-		// Set renting cost for this month
-		monthlyRentingCosts[i] = currentRentingCost
-
-		// Buying cost: loan payment stops after loan duration, but recurring expenses continue
-		if i < config.totalMonths {
-			monthlyBuyingCosts[i] = config.monthlyLoanPayment + currentRecurringExpenses
-
-			// Calculate interest for this month
-			interestPayment := currentBalance * config.monthlyRate
-			// Principal payment is the remainder
-			principalPayment := config.monthlyLoanPayment - interestPayment
-			// Reduce the balance
-			currentBalance -= principalPayment
-
-			// Track cumulative amounts
-			totalPrincipalPaid += principalPayment
-			totalInterestPaid += interestPayment
-
-			// Store remaining balance after this payment
-			remainingLoanBalance[i] = currentBalance
-			cumulativePrincipalPaid[i] = totalPrincipalPaid
-			cumulativeInterestPaid[i] = totalInterestPaid
-		} else {
-			// After loan is paid off, only recurring expenses remain
-			monthlyBuyingCosts[i] = currentRecurringExpenses
-			remainingLoanBalance[i] = 0
-			cumulativePrincipalPaid[i] = totalPrincipalPaid
-			cumulativeInterestPaid[i] = totalInterestPaid
-		}
+// movingCostSummary describes the moving assumption, e.g. "3.0K per move, renters moving every 3 years"
+func movingCostSummary() string {
+	if config.RentMoveMonths <= 0 {
+		return fmt.Sprintf("%s per move, renters moving once", formatCurrency(config.MovingCost))
 	}
-
-	// Calculate KEEP investment tracking arrays
-	calculateKeepInvestmentTracking(maxMonths)
+	every := fmt.Sprintf("%d months", config.RentMoveMonths)
+	if config.RentMoveMonths%12 == 0 {
+		every = fmt.Sprintf("%d years", config.RentMoveMonths/12)
+	}
+	return fmt.Sprintf("%s per move, renters moving every %s", formatCurrency(config.MovingCost), every)
 }
 
-// calculateKeepInvestmentTracking populates investment tracking arrays for KEEP scenario
-func calculateKeepInvestmentTracking(maxMonths int) {
-	monthlyKeepInvestmentValue = make([]float64, maxMonths)
-	monthlyKeepRealCosts = make([]float64, maxMonths)
-	monthlyKeepNetPosition = make([]float64, maxMonths)
-
-	investmentValue := 0.0
-	totalRealCosts := 0.0
-	monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
+// upfrontRentingNote explains the renter's move-in costs for table notes
+func upfrontRentingNote() string {
+	note := fmt.Sprintf("Move-in costs of %s are paid upfront", formatCurrency(calc.UpfrontRentingCosts(&config)))
+	if calc.PrepaidLastMonthRent(&config) > 0 {
+		note += fmt.Sprintf("; the prepaid last month (%s) covers the final month, so it's recovered at the end", formatCurrency(calc.PrepaidLastMonthRent(&config)))
+	}
+	return note + "."
+}
 
-	for i := 0; i < maxMonths; i++ {
-		monthlyCost := monthlyBuyingCosts[i]
+// displayUpfrontRentingCosts prints the broker fee and first & last month input lines, when set
+func displayUpfrontRentingCosts(labelStyle lipgloss.Style) {
+	if config.RentBrokerFee > 0 {
+		fmt.Printf("  %s: %s (non-recoverable)\n", labelStyle.Render("Broker Fee"), formatCurrency(config.RentBrokerFee))
+	}
+	if config.FirstLastMonth {
+		fmt.Printf("  %s: Yes (last month's rent of %s paid at move-in)\n", labelStyle.Render("First & Last Month"), formatCurrency(calc.PrepaidLastMonthRent(&config)))
+	}
+}
 
-		if monthlyCost < 0 {
-			// Income: invest it
-			investmentValue += -monthlyCost
-		} else if monthlyCost > 0 {
-			// Expense: first use investment value, then real costs
-			if investmentValue >= monthlyCost {
-				investmentValue -= monthlyCost
-			} else {
-				// Use up all investment, remainder is real cost
-				deficit := monthlyCost - investmentValue
-				investmentValue = 0
-				totalRealCosts += deficit
-			}
-		}
+// displayRentalMetrics displays cap rate and cash-on-cash return for keeping the asset as a rental (first year)
+func displayRentalMetrics() {
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
+	noteStyle := re.NewStyle().Italic(true).Foreground(MonokaiGrey)
 
-		// Compound whatever investment value remains
-		investmentValue *= (1 + monthlyInvestmentRate)
+	// Net operating income excludes the mortgage; cash flow is what's left after both loans' scheduled payments
+	annualIncome := calc.EffectiveMonthlyRentalIncome(&config) * 12
+	noi := annualIncome - config.AnnualInsurance - config.AnnualTaxes - config.MonthlyExpenses*12
+	debtService := config.MonthlyLoanPayment * float64(min(12, config.TotalMonths))
+	if config.SecondLoanAmount > 0 {
+		debtService += config.SecondLoanPayment * float64(min(12, config.SecondLoanMonths))
+	}
+	annualCashFlow := noi - debtService
 
-		// Store values for this month
-		monthlyKeepInvestmentValue[i] = investmentValue
-		monthlyKeepRealCosts[i] = totalRealCosts
-		monthlyKeepNetPosition[i] = investmentValue - totalRealCosts
+	fmt.Println()
+	fmt.Printf("  %s: %s (effective rent %s - operating costs %s)\n", labelStyle.Render("Net Operating Income"),
+		formatCurrency(noi), formatCurrency(annualIncome), formatCurrency(annualIncome-noi))
+	fmt.Printf("  %s: %.2f%% (NOI / current market value)\n", labelStyle.Render("Cap Rate"), noi/config.CurrentMarketValue*100)
+	if config.Downpayment > 0 {
+		fmt.Printf("  %s: %.2f%% (annual cash flow %s / equity %s)\n", labelStyle.Render("Cash-on-Cash Return"),
+			annualCashFlow/config.Downpayment*100, formatCurrency(annualCashFlow), formatCurrency(config.Downpayment))
+	} else {
+		fmt.Printf("  %s: n/a (no equity invested)\n", labelStyle.Render("Cash-on-Cash Return"))
 	}
+	fmt.Println(noteStyle.Render("  First-year, pre-tax figures. Equity is the cash you'd free up by selling today."))
 }
 
 // calculateRentingNetWorth calculates net worth for the renting scenario
 // Uses month-by-month calculation: investment grows from downpayment + monthly savings
 func calculateRentingNetWorth(months int) float64 {
-	// Start with downpayment minus deposit as initial investment
-	investmentValue := config.downpayment - config.rentDeposit
-	monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
+	return calculateRentingNetWorthForRent(months, config.MonthlyRent)
+}
 
-	// For each month: calculate savings, add to investment, grow investment
-	for i := 0; i < months; i++ {
-		// Monthly savings = buying cost - renting cost
-		monthlySavings := monthlyBuyingCosts[i] - monthlyRentingCosts[i]
+// calculateRentingNetWorthForRent calculates renting net worth as if the base monthly rent were monthlyRent
+// The difference from the configured rent is inflated annually on top of the monthly renting costs
+func calculateRentingNetWorthForRent(months int, monthlyRent float64) float64 {
+	return calc.RentingNetWorth(&config, monthly, months, monthlyRent, rentingAnnualReturns(&config))
+}
+
+// rentingAnnualReturns returns the year-by-year returns for the renting investment
+// Backtested historical returns come first, then the investment return rates for remaining years
+func rentingAnnualReturns(cfg *calc.Inputs) []float64 {
+	returns := append([]float64{}, backtestReturns...)
+	for year := len(backtestReturns); year < len(cfg.InvestmentReturnRates) || year == len(backtestReturns); year++ {
+		returns = append(returns, calc.RateForYear(cfg.InvestmentReturnRates, year))
+	}
+	return returns
+}
+
+// breakEvenHorizonMonths is the horizon at which break-even rent is solved
+const breakEvenHorizonMonths = 120
+
+// calculateBreakEvenRent finds the monthly rent at which renting and buying net worth tie at the given horizon
+// Returns false if no non-negative rent achieves a tie
+func calculateBreakEvenRent(months int) (float64, bool) {
+	_, _, buyingNetWorth := calculateNetWorth(months)
+
+	// Renting net worth falls as rent rises, so bisect between a rent where renting wins and one where it loses
+	low := 0.0
+	if calculateRentingNetWorthForRent(months, low) < buyingNetWorth {
+		return 0, false
+	}
 
-		// Add savings to investment
-		investmentValue += monthlySavings
+	high := math.Max(config.MonthlyRent, 1000)
+	for i := 0; calculateRentingNetWorthForRent(months, high) > buyingNetWorth; i++ {
+		if i >= 60 {
+			return 0, false
+		}
+		high *= 2
+	}
 
-		// Apply monthly growth
-		investmentValue *= (1 + monthlyInvestmentRate)
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if calculateRentingNetWorthForRent(months, mid) > buyingNetWorth {
+			low = mid
+		} else {
+			high = mid
+		}
 	}
 
-	// Add back 75% of deposit (recoverable)
-	recoverableDeposit := config.rentDeposit * 0.75
+	return (low + high) / 2, true
+}
+
+// displayBreakEvenRent prints the monthly rent at which renting and buying tie at the given horizon
+func displayBreakEvenRent(months int) {
+	re := newRenderer()
+	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
+
+	horizon := fmt.Sprintf("%dy", months/12)
+	rent, ok := calculateBreakEvenRent(months)
+	if !ok {
+		fmt.Printf("\n  %s: n/a (buying wins at %s even with zero rent)\n", labelStyle.Render("Break-even rent"), horizon)
+		return
+	}
 
-	return investmentValue + recoverableDeposit
+	// Whole units in the display currency, grouped for the --locale like other full amounts
+	fmt.Printf("\n  %s: %s/mo (renting and buying net worth tie at %s)\n",
+		labelStyle.Render("Break-even rent"), localizeAmount(strconv.Itoa(int(math.Round(rent*fxRate)))), horizon)
 }
 
 // displayInputParametersSellVsKeep displays input parameters for SELL vs KEEP scenario
 func displayInputParametersSellVsKeep(md *MarketData) {
-	re := lipgloss.NewRenderer(os.Stdout)
+	re := newRenderer()
 	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
 	labelStyle := re.NewStyle().Foreground(MonokaiCyan)
 	groupStyle := re.NewStyle().Foreground(MonokaiOrange).Bold(true)
@@ -1544,93 +3256,141 @@ func displayInputParametersSellVsKeep(md *MarketData) {
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ECONOMIC ASSUMPTIONS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Inflation Rate"), config.inflationRate)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Investment Return Rate"), config.investmentReturnRate)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Inflation Rate"), formatRateSchedule(config.InflationRates))
+	if inflationTiming == "start" {
+		fmt.Printf("  %s: Start of year (year-one costs are already inflated, so every period shifts up by a year of inflation)\n", labelStyle.Render("Inflation Timing"))
+	}
+	if displayCurrency != "" {
+		fmt.Printf("  %s: %s at %s per unit of the input currency (calculations stay in the input currency)\n", labelStyle.Render("Display Currency"), displayCurrency, strconv.FormatFloat(fxRate, 'f', -1, 64))
+	}
+	if cpiInflation != nil {
+		fmt.Printf("    %s\n", cpiFootnote(cpiInflation))
+	}
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Investment Return Rate"), formatRateSchedule(config.InvestmentReturnRates))
+	if config.InvestmentTaxRate > 0 {
+		fmt.Printf("  %s: %.2f%% of investment returns (tax drag)\n", labelStyle.Render("Investment Tax Rate"), config.InvestmentTaxRate)
+	}
 
 	// Display market averages with ticker symbols in cyan
 	if md != nil && len(md.VOO) > 0 {
 		vooAvg, qqqAvg, vtiAvg, bndAvg, mix6040Avg := calculateMarketAverages(md)
 		if vooAvg > 0 {
 			tickerStyle := re.NewStyle().Foreground(MonokaiCyan)
-			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%\n",
+			fmt.Printf("    Market Averages (10y): %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%, %s %.1f%%%s\n",
 				tickerStyle.Render("VOO"), vooAvg,
 				tickerStyle.Render("QQQ"), qqqAvg,
 				tickerStyle.Render("VTI"), vtiAvg,
 				tickerStyle.Render("BND"), bndAvg,
-				tickerStyle.Render("60/40"), mix6040Avg)
+				tickerStyle.Render("60/40"), mix6040Avg, mixAverageText(md, tickerStyle))
 		}
 	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("ASSET"))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Original Purchase Price"), formatCurrency(config.purchasePrice))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Market Value"), formatCurrency(config.currentMarketValue))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Equity"), formatCurrency(config.downpayment))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Original Purchase Price"), formatCurrency(config.PurchasePrice))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Market Value"), formatCurrency(config.CurrentMarketValue))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Current Equity"), formatCurrency(config.Downpayment))
 
-	if config.loanAmount > 0 {
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Balance"), formatCurrency(config.loanAmount))
-		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.annualRate)
+	if config.LoanAmount > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Balance"), formatCurrency(config.LoanAmount))
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Loan Rate"), config.AnnualRate)
 		loanDurationStr := ""
-		if config.totalMonths%12 == 0 {
-			loanDurationStr = fmt.Sprintf("%dy", config.totalMonths/12)
+		if config.TotalMonths%12 == 0 {
+			loanDurationStr = fmt.Sprintf("%dy", config.TotalMonths/12)
 		} else {
-			loanDurationStr = fmt.Sprintf("%d months", config.totalMonths)
+			loanDurationStr = fmt.Sprintf("%d months", config.TotalMonths)
 		}
 		fmt.Printf("  %s: %s\n", labelStyle.Render("Remaining Loan Term"), loanDurationStr)
+		if config.LumpSumAmount > 0 {
+			fmt.Printf("  %s: %s (month %d)\n", labelStyle.Render("Lump-Sum Prepayment"), formatCurrency(config.LumpSumAmount), config.LumpSumMonth)
+		}
 	} else {
 		fmt.Printf("  %s: Fully paid off\n", labelStyle.Render("Loan Status"))
 	}
+	if config.SecondLoanAmount > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Second Loan Balance"), secondLoanSummary())
+	}
 
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.annualInsurance))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.annualTaxes))
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.monthlyExpenses))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Tax & Insurance"), formatCurrency(config.AnnualInsurance))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Other Annual Costs"), formatCurrency(config.AnnualTaxes))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Expenses"), formatCurrency(config.MonthlyExpenses))
+	if config.UtilitiesDiff != 0 {
+		fmt.Printf("  %s: %s/month vs renting\n", labelStyle.Render("Utilities Difference"), formatCurrency(config.UtilitiesDiff))
+	}
+	if len(config.OneTimeCosts) > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("One-Time Costs"), formatOneTimeCosts(config.OneTimeCosts))
+	}
+	if config.MaintenancePct > 0 {
+		fmt.Printf("  %s: %.2f%% of asset value per year (%s/mo in year 1)\n", labelStyle.Render("Maintenance"), config.MaintenancePct, formatCurrency(calc.MonthlyMaintenance(&config, 0)))
+	}
+	if config.ValueBasedEscrow {
+		fmt.Printf("  %s: Tax & insurance scale with the asset value (%.2f%% of value)\n", labelStyle.Render("Value-Based Escrow"), config.AnnualInsurance/calc.StartingAssetValue(&config)*100)
+	}
+	if config.ReassessYears > 0 {
+		fmt.Printf("  %s: Every %d years to %.2f%% of the asset value, growing at most %.1f%%/year in between\n", labelStyle.Render("Tax Reassessment"), config.ReassessYears, config.AnnualInsurance/calc.StartingAssetValue(&config)*100, config.ReassessCapPct)
+	}
 
-	// Format appreciation rates
-	appreciationRateStr := ""
-	if len(appreciationRates) == 1 {
-		appreciationRateStr = fmt.Sprintf("%.2f%% (all years)", appreciationRates[0])
-	} else {
-		rateStrs := make([]string, len(appreciationRates))
-		for i, rate := range appreciationRates {
-			if i == len(appreciationRates)-1 {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d+)", rate, i+1)
-			} else {
-				rateStrs[i] = fmt.Sprintf("%.2f%% (year %d)", rate, i+1)
-			}
+	if config.IncludeRentalIncome {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rental Income"), formatCurrency(config.MonthlyRentalIncome))
+		if config.VacancyRate > 0 || config.ManagementFeePct > 0 {
+			fmt.Printf("  %s: %.1f%% vacancy, %.1f%% management fee\n", labelStyle.Render("Rental Assumptions"), config.VacancyRate, config.ManagementFeePct)
+		}
+		if config.DepreciableBasis > 0 {
+			fmt.Printf("  %s: %s basis over 27.5 years (%s/mo), recaptured at %d%% on sale\n", labelStyle.Render("Depreciation"), formatCurrency(config.DepreciableBasis), formatCurrency(calc.MonthlyDepreciation(&config, 0)), calc.DepreciationRecaptureRate)
+		}
+		if config.RentalIncomeTaxRate > 0 {
+			fmt.Printf("  %s: %.1f%% of rental income after deductible costs and depreciation\n", labelStyle.Render("Rental Income Tax"), config.RentalIncomeTaxRate)
 		}
-		appreciationRateStr = strings.Join(rateStrs, ", ")
 	}
+
+	// Format appreciation rates
+	appreciationRateStr := formatRateSchedule(config.AppreciationRates)
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Appreciation Rate (if keeping)"), appreciationRateStr)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost (if keeping)"), formatCurrency(config.totalMonthlyBuyingCost))
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Cost (if keeping)"), formatCurrency(config.TotalMonthlyBuyingCost))
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("INVESTING (if selling)"))
 
 	// Check if renting analysis is included
-	includeRenting, _ := getFloatValue("include_renting_sell")
-	if includeRenting > 0 {
+	if config.IncludeRentingSell {
 		fmt.Printf("  %s: Yes\n", labelStyle.Render("Include Renting Analysis"))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.rentDeposit))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.monthlyRent))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.annualRentCosts))
-		fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Renting Cost"), formatCurrency(config.totalMonthlyRentingCost))
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Rental Deposit"), formatCurrency(config.RentDeposit))
+		displayUpfrontRentingCosts(labelStyle)
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Monthly Rent"), formatCurrency(config.MonthlyRent))
+		if calc.RentStepped(&config) {
+			fmt.Printf("  %s: %s at each yearly renewal (instead of inflation)\n", labelStyle.Render("Rent Step"), formatRentStep())
+		}
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Annual Rent Costs"), formatCurrency(config.AnnualRentCosts))
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Total Monthly Renting Cost"), formatCurrency(config.TotalMonthlyRentingCost))
+		if config.MovingCost > 0 {
+			fmt.Printf("  %s: %s\n", labelStyle.Render("Moving Cost"), movingCostSummary())
+		}
 	} else {
 		fmt.Printf("  %s: No\n", labelStyle.Render("Include Renting Analysis"))
 	}
 
 	fmt.Println()
 	fmt.Println(groupStyle.Render("SELLING COSTS"))
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.agentCommission)
-	fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.stagingCosts))
+	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Agent Commission"), config.AgentCommission)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Staging/Selling Costs"), formatCurrency(config.StagingCosts))
+	if config.TransferTaxPct > 0 {
+		fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Transfer Tax"), config.TransferTaxPct)
+	}
+	if config.SellerConcessions > 0 {
+		fmt.Printf("  %s: %s\n", labelStyle.Render("Seller Concessions"), formatCurrency(config.SellerConcessions))
+	}
+	if config.PrepayPenaltyPct > 0 {
+		fmt.Printf("  %s: %.2f%% of the loan payoff within %s\n", labelStyle.Render("Prepayment Penalty"), config.PrepayPenaltyPct, formatPenaltyYears())
+	}
 
 	// Format tax-free limits
 	taxFreeLimitStr := ""
-	if len(taxFreeLimits) == 1 {
-		taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(taxFreeLimits[0]))
+	if len(config.TaxFreeLimits) == 1 {
+		taxFreeLimitStr = fmt.Sprintf("%s (all years)", formatCurrency(config.TaxFreeLimits[0]))
 	} else {
-		limitStrs := make([]string, len(taxFreeLimits))
-		for i, limit := range taxFreeLimits {
-			if i == len(taxFreeLimits)-1 {
+		limitStrs := make([]string, len(config.TaxFreeLimits))
+		for i, limit := range config.TaxFreeLimits {
+			if i == len(config.TaxFreeLimits)-1 {
 				limitStrs[i] = fmt.Sprintf("%s (year %d+)", formatCurrency(limit), i+1)
 			} else {
 				limitStrs[i] = fmt.Sprintf("%s (year %d)", formatCurrency(limit), i+1)
@@ -1639,87 +3399,38 @@ func displayInputParametersSellVsKeep(md *MarketData) {
 		taxFreeLimitStr = strings.Join(limitStrs, ", ")
 	}
 	fmt.Printf("  %s: %s\n", labelStyle.Render("Tax-Free Gains Limit"), taxFreeLimitStr)
-	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.capitalGainsTax)
+	fmt.Printf("  %s: %s\n", labelStyle.Render("Primary Residence"), primaryResidenceStr())
+	fmt.Printf("  %s: %.2f%%\n", labelStyle.Render("Capital Gains Tax Rate"), config.CapitalGainsTax)
 }
 
-// calculateSellNetWorth calculates net worth if selling at month 0 and investing proceeds
-func calculateSellNetWorth(months int) float64 {
-	// Calculate net proceeds from selling now
-	salePrice := config.currentMarketValue
-	agentFee := salePrice * (config.agentCommission / 100)
-	totalSellingCosts := agentFee + config.stagingCosts
-	loanPayoff := config.loanAmount
-	// Capital gains with selling costs deducted
-	capitalGains := salePrice - config.purchasePrice - totalSellingCosts
-	// Use first tax-free limit (selling now = year 0)
-	taxFreeLimit := taxFreeLimits[0]
-	taxableGains := math.Max(0, capitalGains-taxFreeLimit)
-	taxOnGains := taxableGains * (config.capitalGainsTax / 100)
-	netProceeds := salePrice - totalSellingCosts - loanPayoff - taxOnGains
-
-	// Check if we need to account for renting
-	includeRenting, _ := getFloatValue("include_renting_sell")
-
-	if includeRenting > 0 {
-		// Start investment with net proceeds minus rental deposit
-		investmentValue := netProceeds - config.rentDeposit
-		monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-		// For each month: subtract rental costs, grow investment
-		for i := 0; i < months; i++ {
-			// Subtract renting costs
-			investmentValue -= monthlyRentingCosts[i]
-
-			// Apply monthly growth
-			investmentValue *= (1 + monthlyInvestmentRate)
-		}
-
-		// Add back 75% of rental deposit (recoverable)
-		recoverableDeposit := config.rentDeposit * 0.75
-		return investmentValue + recoverableDeposit
-	} else {
-		// Just invest the proceeds without rental costs
-		investmentValue := netProceeds
-		monthlyInvestmentRate := config.investmentReturnRate / 100 / 12
-
-		// Simple monthly compounding
-		for i := 0; i < months; i++ {
-			investmentValue *= (1 + monthlyInvestmentRate)
-		}
-
-		return investmentValue
+// primaryResidenceStr describes the primary-residence status used for the tax-free exclusion
+func primaryResidenceStr() string {
+	if !config.PrimaryResidence {
+		return "No (tax-free limit not applied)"
 	}
+	return fmt.Sprintf("Yes (%d months occupied so far; limit applies with %d of the last %d months)", config.MonthsOccupied, calc.ExclusionRequiredMonths, calc.ExclusionWindowMonths)
 }
 
-// calculateKeepNetWorth calculates net worth if keeping the asset and selling at future point
-func calculateKeepNetWorth(months int) float64 {
-	// Get net proceeds from selling at this future point
-	// This accounts for appreciation, selling costs, loan payoff, and capital gains tax
-	_, _, _, _, _, netProceeds := calculateSaleProceeds(months)
-
-	// Get net position from pre-calculated arrays
-	monthIndex := months - 1
-	if monthIndex < 0 {
-		monthIndex = 0
-	}
-	if monthIndex >= len(monthlyKeepNetPosition) {
-		monthIndex = len(monthlyKeepNetPosition) - 1
-	}
-	netPosition := monthlyKeepNetPosition[monthIndex]
+// calculateSellNetWorth calculates net worth of the current config if selling at month 0 and investing proceeds
+func calculateSellNetWorth(months int) float64 {
+	return calc.SellNetWorth(&config, monthly, months)
+}
 
-	return netProceeds + netPosition
+// calculateKeepNetWorth calculates net worth of the current config if keeping the asset and selling at future point
+func calculateKeepNetWorth(months int) float64 {
+	return calc.KeepNetWorth(&config, monthly, months)
 }
 
 // displaySellVsKeepComparison displays the comparison table for SELL vs KEEP
 func displaySellVsKeepComparison() {
-	periods := getPeriods(config.totalMonths, config.include30Year > 0)
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
 
 	// Check if renting analysis is included
-	includeRenting, _ := getFloatValue("include_renting_sell")
+	includeRenting := config.IncludeRentingSell
 
 	// Build table rows with Cum. Expenses columns
 	var rows [][]string
-	if includeRenting > 0 {
+	if includeRenting {
 		rows = [][]string{
 			{"Period", "SELL Cum. Exp", "SELL Net Worth", "KEEP Net Position", "KEEP Net Proceeds", "KEEP - SELL"},
 		}
@@ -1740,50 +3451,52 @@ func displaySellVsKeepComparison() {
 		if monthIndex < 0 {
 			monthIndex = 0
 		}
-		if monthIndex >= len(monthlyKeepNetPosition) {
-			monthIndex = len(monthlyKeepNetPosition) - 1
-		}
-		keepNetPosition := monthlyKeepNetPosition[monthIndex]
+		keepNetPosition := monthly.KeepNetPosition[monthIndex]
 
-		if includeRenting > 0 {
+		if includeRenting {
 			// Calculate cumulative rental expenses for SELL
-			cumulativeRentExpenses := config.rentDeposit // Initial deposit
+			cumulativeRentExpenses := config.RentDeposit + calc.UpfrontRentingCosts(&config) // Initial deposit and move-in costs
 			for i := 0; i < period.months; i++ {
-				cumulativeRentExpenses += monthlyRentingCosts[i]
+				cumulativeRentExpenses += monthly.RentingCosts[i]
 			}
-			// Subtract recoverable deposit (75%)
-			cumulativeRentExpenses -= config.rentDeposit * 0.75
+			// Subtract recoverable deposit (75%) and prepaid last month
+			cumulativeRentExpenses -= calc.RecoverableRentingCosts(&config)
 
 			rows = append(rows, []string{
 				"NET " + period.label,
-				formatCurrency(cumulativeRentExpenses),
-				formatCurrency(sellNetWorth),
-				formatCurrency(keepNetPosition),
-				formatCurrency(keepNetWorth),
-				formatCurrency(difference),
+				formatCurrency(deflate(cumulativeRentExpenses, period.months)),
+				formatCurrency(deflate(sellNetWorth, period.months)),
+				formatCurrency(deflate(keepNetPosition, period.months)),
+				formatCurrency(deflate(keepNetWorth, period.months)),
+				formatCurrency(deflate(difference, period.months)),
 			})
 		} else {
 			rows = append(rows, []string{
 				"NET " + period.label,
-				formatCurrency(sellNetWorth),
-				formatCurrency(keepNetPosition),
-				formatCurrency(keepNetWorth),
-				formatCurrency(difference),
+				formatCurrency(deflate(sellNetWorth, period.months)),
+				formatCurrency(deflate(keepNetPosition, period.months)),
+				formatCurrency(deflate(keepNetWorth, period.months)),
+				formatCurrency(deflate(difference, period.months)),
 			})
 		}
 	}
 
 	// Build note text
 	noteText := ""
-	if includeRenting > 0 {
+	if includeRenting {
 		noteText = "Note: 'SELL Cum. Exp' = Total rental costs (deposit + all monthly rent - 75% recoverable deposit).\n\n"
-		noteText += fmt.Sprintf("'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return, minus rental costs (inflated annually at %.1f%%).\n\n", config.investmentReturnRate, config.inflationRate)
+		noteText += fmt.Sprintf("'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return, minus rental costs (inflated annually at %.1f%%).\n\n", config.InvestmentReturnRate, config.InflationRate)
+		if config.MovingCost > 0 {
+			noteText += fmt.Sprintf("Moving costs if selling: %s (inflated).\n\n", movingCostSummary())
+		}
 	} else {
-		noteText = fmt.Sprintf("Note: 'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return with monthly compounding.\n\n", config.investmentReturnRate)
+		noteText = fmt.Sprintf("Note: 'SELL Net Worth' = Net proceeds from selling today invested at %.0f%% return with monthly compounding.\n\n", config.InvestmentReturnRate)
 	}
-	noteText += fmt.Sprintf("'KEEP Net Position' = Investment value from income (invested at %.0f%% return) minus real out-of-pocket costs (see KEEP Expenses Breakdown for details).\n\n", config.investmentReturnRate)
+	noteText += fmt.Sprintf("'KEEP Net Position' = Investment value from income (invested at %.0f%% return) minus real out-of-pocket costs (see KEEP Expenses Breakdown for details).\n\n", config.InvestmentReturnRate)
 	noteText += "'KEEP Net Proceeds' = Net proceeds if keeping and selling at that future point, plus net position (see Sale Proceeds Analysis for sale breakdown).\n\n"
 	noteText += "'KEEP - SELL': Positive values mean keeping wins, negative values mean selling wins."
+	noteText += realDollarsNote()
 
 	displayTable("NET WORTH PROJECTIONS: SELL VS KEEP", rows, noteText, false)
+	recordClipboardTable("NET WORTH PROJECTIONS: SELL VS KEEP", rows, noteText)
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatCurrency(t *testing.T) {
+	savedPrecision, savedUnits := precision, displayUnits
+	defer func() { precision, displayUnits = savedPrecision, savedUnits }()
+	precision = 1
+
+	tests := []struct {
+		units  string
+		amount float64
+		want   string
+	}{
+		{"western", 0, "0.0"},
+		{"western", 999, "999.0"},
+		{"western", 1500, "1.5K"},
+		{"western", 2500000, "2.5M"},
+		{"western", 7.5e9, "7.5B"},
+		{"western", 2.3e12, "2.3T"},
+		{"western", -2.3e12, "-2.3T"},
+		{"indian", 1500, "1.5K"},
+		{"indian", 250000, "2.5L"},
+		{"indian", 12000000, "1.2Cr"},
+		{"indian", 99e10, "99000.0Cr"},
+		{"indian", 2.3e12, "2.3LCr"},
+	}
+	for _, tt := range tests {
+		displayUnits = tt.units
+		if got := formatCurrency(tt.amount); got != tt.want {
+			t.Errorf("formatCurrency(%v) with --units %s = %q, want %q", tt.amount, tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestParseOneTimeCosts(t *testing.T) {
+	tests := []struct {
+		input string
+		want  map[int]float64
+	}{
+		{"", map[int]float64{}},
+		{"36:15K,120:8000", map[int]float64{36: 15000, 120: 8000}},
+		{"36:15K, 36:5K", map[int]float64{36: 20000}},
+		{`36:"15,000",120:8K`, map[int]float64{36: 15000, 120: 8000}},
+		{`"36:1,250,000"`, map[int]float64{36: 1250000}},
+	}
+	for _, tt := range tests {
+		got, err := parseOneTimeCosts(tt.input)
+		if err != nil {
+			t.Errorf("parseOneTimeCosts(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseOneTimeCosts(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"36:15,000", `36:"15,000`, "0:5K", "36"} {
+		if _, err := parseOneTimeCosts(input); err == nil {
+			t.Errorf("parseOneTimeCosts(%q) succeeded, want error", input)
+		}
+	}
+}
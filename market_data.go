@@ -3,32 +3,34 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/lipgloss/table"
 )
 
-const marketDataFile = ".rentobuy_market_data.json"
-
 // MarketData stores historical annual returns
 type MarketData struct {
-	LastUpdated string             `json:"last_updated"`
-	VOO         map[string]float64 `json:"voo"`    // Year -> Annual return % (S&P 500)
-	QQQ         map[string]float64 `json:"qqq"`    // Year -> Annual return % (Nasdaq 100)
-	VTI         map[string]float64 `json:"vti"`    // Year -> Annual return % (Total Stock Market)
-	BND         map[string]float64 `json:"bnd"`    // Year -> Annual return % (Total Bond Market)
+	LastUpdated string                        `json:"last_updated"`
+	VOO         map[string]float64            `json:"voo"`                 // Year -> Annual return % (S&P 500)
+	QQQ         map[string]float64            `json:"qqq"`                 // Year -> Annual return % (Nasdaq 100)
+	VTI         map[string]float64            `json:"vti"`                 // Year -> Annual return % (Total Stock Market)
+	BND         map[string]float64            `json:"bnd"`                 // Year -> Annual return % (Total Bond Market)
+	Overrides   map[string]map[string]float64 `json:"overrides,omitempty"` // Ticker -> Year -> manual return % (survives refetches)
 }
 
 // YahooChartResponse represents the JSON response from Yahoo Finance chart API
 type YahooChartResponse struct {
 	Chart struct {
 		Result []struct {
-			Timestamp []int64 `json:"timestamp"`
+			Timestamp  []int64 `json:"timestamp"`
 			Indicators struct {
 				Adjclose []struct {
 					Adjclose []float64 `json:"adjclose"`
@@ -38,8 +40,38 @@ type YahooChartResponse struct {
 	} `json:"chart"`
 }
 
+// Retry settings for Yahoo Finance requests
+const (
+	fetchAttempts    = 3
+	fetchBaseBackoff = 500 * time.Millisecond
+)
+
 // fetchYahooFinanceData fetches historical price data from Yahoo Finance using chart API
+// Network errors, 429 and 5xx responses are retried with exponential backoff and jitter
 func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]string, error) {
+	var err error
+	for attempt := 0; attempt < fetchAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := fetchBaseBackoff << (attempt - 1)
+			time.Sleep(backoff + time.Duration(rand.Int63n(int64(backoff/2))))
+		}
+
+		var records [][]string
+		var retryable bool
+		records, retryable, err = fetchYahooFinanceAttempt(ticker, startDate, endDate)
+		if err == nil {
+			return records, nil
+		}
+		if !retryable {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%v (after %d attempts)", err, fetchAttempts)
+}
+
+// fetchYahooFinanceAttempt makes a single chart API request
+// Also reports whether a failure is transient and worth retrying
+func fetchYahooFinanceAttempt(ticker string, startDate, endDate time.Time) ([][]string, bool, error) {
 	// Convert to Unix timestamps
 	period1 := startDate.Unix()
 	period2 := endDate.Unix()
@@ -51,7 +83,7 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 	// Create request with headers
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, false, fmt.Errorf("failed to create request: %v", err)
 	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
 
@@ -59,23 +91,24 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch data: %v", err)
+		return nil, true, fmt.Errorf("failed to fetch data: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("yahoo finance returned status %d", resp.StatusCode)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, fmt.Errorf("yahoo finance returned status %d", resp.StatusCode)
 	}
 
 	// Parse JSON
 	var chartResp YahooChartResponse
 	err = json.NewDecoder(resp.Body).Decode(&chartResp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+		return nil, false, fmt.Errorf("failed to parse JSON: %v", err)
 	}
 
 	if len(chartResp.Chart.Result) == 0 {
-		return nil, fmt.Errorf("no data returned")
+		return nil, false, fmt.Errorf("no data returned")
 	}
 
 	result := chartResp.Chart.Result[0]
@@ -83,7 +116,7 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 	adjCloses := result.Indicators.Adjclose[0].Adjclose
 
 	if len(timestamps) != len(adjCloses) {
-		return nil, fmt.Errorf("data length mismatch")
+		return nil, false, fmt.Errorf("data length mismatch")
 	}
 
 	// Convert to CSV format: Date, Adj Close
@@ -94,7 +127,7 @@ func fetchYahooFinanceData(ticker string, startDate, endDate time.Time) ([][]str
 		records = append(records, []string{date, adjClose})
 	}
 
-	return records, nil
+	return records, false, nil
 }
 
 // calculateAnnualReturns calculates annual returns from daily price data
@@ -191,8 +224,6 @@ func loadMarketData() (*MarketData, error) {
 
 // saveMarketData saves market data to cache file
 func saveMarketData(md *MarketData) error {
-	md.LastUpdated = time.Now().Format("2006-01-02")
-
 	data, err := json.MarshalIndent(md, "", "  ")
 	if err != nil {
 		return err
@@ -201,6 +232,9 @@ func saveMarketData(md *MarketData) error {
 	return os.WriteFile(marketDataFile, data, 0644)
 }
 
+// marketDataTTL is how long cached market data stays fresh (set by --market-ttl)
+var marketDataTTL = 30 * 24 * time.Hour
+
 // updateMarketData fetches and updates market data if needed
 func updateMarketData() (*MarketData, error) {
 	md, err := loadMarketData()
@@ -212,11 +246,11 @@ func updateMarketData() (*MarketData, error) {
 	now := time.Now()
 	needsUpdate := false
 
-	// Update if cache is older than 1 month
+	// Update if cache is older than the --market-ttl (0 updates every run)
 	if md.LastUpdated != "" {
 		lastUpdate, err := time.Parse("2006-01-02", md.LastUpdated)
 		if err == nil {
-			if now.Sub(lastUpdate) > 30*24*time.Hour {
+			if marketDataTTL == 0 || now.Sub(lastUpdate) > marketDataTTL {
 				needsUpdate = true
 			}
 		}
@@ -251,30 +285,43 @@ func updateMarketData() (*MarketData, error) {
 		{"BND", &md.BND},
 	}
 
-	// Fetch each ticker
+	// Fetch all tickers concurrently, merging results into the cache under a mutex
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, maxConcurrentFetches)
+	errs := make([]error, len(tickers))
 	for i, ticker := range tickers {
-		records, err := fetchYahooFinanceData(ticker.symbol, startDate, endDate)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch %s data: %v", ticker.symbol, err)
-		}
-
-		returns, err := calculateAnnualReturns(records)
-		if err != nil {
-			return nil, fmt.Errorf("failed to calculate %s returns: %v", ticker.symbol, err)
-		}
+		wg.Add(1)
+		go func(i int, symbol string, target *map[string]float64) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			returns, err := fetchTickerReturns(symbol, startDate, endDate)
+			if err != nil {
+				errs[i] = err
+				return
+			}
 
-		// Update cache with new data
-		for year, ret := range returns {
-			(*ticker.target)[year] = ret
-		}
+			// Update cache with new data
+			mu.Lock()
+			for year, ret := range returns {
+				(*target)[year] = ret
+			}
+			mu.Unlock()
+		}(i, ticker.symbol, ticker.target)
+	}
+	wg.Wait()
 
-		// Wait a bit to avoid rate limiting (except on last iteration)
-		if i < len(tickers)-1 {
-			time.Sleep(1 * time.Second)
+	// Report the first failure in ticker order
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	// Save to cache
+	md.LastUpdated = now.Format("2006-01-02")
 	err = saveMarketData(md)
 	if err != nil {
 		return nil, fmt.Errorf("failed to save cache: %v", err)
@@ -285,6 +332,73 @@ func updateMarketData() (*MarketData, error) {
 	return md, nil
 }
 
+// addMarketOverrides parses overrides like "VOO:2022:-18.1,QQQ:2021:27" into md's overrides; the caller saves them
+func addMarketOverrides(md *MarketData, spec string) error {
+	for _, part := range strings.Split(spec, ",") {
+		pieces := strings.Split(strings.TrimSpace(part), ":")
+		if len(pieces) != 3 {
+			return fmt.Errorf("%q is not TICKER:YEAR:RETURN", part)
+		}
+		ticker := strings.ToUpper(strings.TrimSpace(pieces[0]))
+		if _, ok := tickerReturns(md, ticker); !ok {
+			return fmt.Errorf("unknown ticker %s (expected VOO, QQQ, VTI, or BND)", ticker)
+		}
+		year := strings.TrimSpace(pieces[1])
+		if _, err := strconv.Atoi(year); err != nil || len(year) != 4 {
+			return fmt.Errorf("invalid year %q", year)
+		}
+		ret, err := strconv.ParseFloat(strings.TrimSpace(pieces[2]), 64)
+		if err != nil {
+			return fmt.Errorf("invalid return %q for %s %s", pieces[2], ticker, year)
+		}
+
+		if md.Overrides == nil {
+			md.Overrides = make(map[string]map[string]float64)
+		}
+		if md.Overrides[ticker] == nil {
+			md.Overrides[ticker] = make(map[string]float64)
+		}
+		md.Overrides[ticker][year] = ret
+	}
+	return nil
+}
+
+// applyMarketOverrides writes the saved manual returns over the fetched ones
+func applyMarketOverrides(md *MarketData) {
+	for ticker, years := range md.Overrides {
+		returns, ok := tickerReturns(md, ticker)
+		if !ok {
+			continue
+		}
+		for year, ret := range years {
+			returns[year] = ret
+		}
+	}
+}
+
+// isOverridden reports whether a ticker's return for year was set with --override
+func isOverridden(md *MarketData, ticker, year string) bool {
+	_, ok := md.Overrides[ticker][year]
+	return ok
+}
+
+// maxConcurrentFetches caps how many tickers are fetched from Yahoo Finance at once
+const maxConcurrentFetches = 4
+
+// fetchTickerReturns fetches a ticker's history and calculates its annual returns
+func fetchTickerReturns(symbol string, startDate, endDate time.Time) (map[string]float64, error) {
+	records, err := fetchYahooFinanceData(symbol, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s data: %v", symbol, err)
+	}
+
+	returns, err := calculateAnnualReturns(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate %s returns: %v", symbol, err)
+	}
+	return returns, nil
+}
+
 // calculateMarketAverages calculates 10-year averages for all ETFs
 func calculateMarketAverages(md *MarketData) (voo, qqq, vti, bnd, mix6040 float64) {
 	if md == nil {
@@ -328,8 +442,117 @@ func calculateMarketAverages(md *MarketData) (voo, qqq, vti, bnd, mix6040 float6
 	return
 }
 
-// displayMarketData shows historical returns and averages
+// mixWeight is one ticker's share of a --mix portfolio
+type mixWeight struct {
+	ticker string
+	weight float64
+}
+
+// portfolioMix is the custom blend set by --mix (nil when unset)
+var portfolioMix []mixWeight
+
+// parsePortfolioMix parses a mix like "VTI:0.5,BND:0.3,QQQ:0.2"
+// Weights must sum to 1 and every ticker must have market data
+func parsePortfolioMix(spec string, md *MarketData) ([]mixWeight, error) {
+	var mix []mixWeight
+	total := 0.0
+	for _, part := range strings.Split(spec, ",") {
+		pieces := strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("%q is not TICKER:WEIGHT", part)
+		}
+		ticker := strings.ToUpper(strings.TrimSpace(pieces[0]))
+		weight, err := strconv.ParseFloat(strings.TrimSpace(pieces[1]), 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid weight for %s", ticker)
+		}
+		returns, ok := tickerReturns(md, ticker)
+		if !ok {
+			return nil, fmt.Errorf("unknown ticker %s (expected VOO, QQQ, VTI, or BND)", ticker)
+		}
+		if len(returns) == 0 {
+			return nil, fmt.Errorf("no market data for %s", ticker)
+		}
+		mix = append(mix, mixWeight{ticker, weight})
+		total += weight
+	}
+	if math.Abs(total-1) > 0.001 {
+		return nil, fmt.Errorf("weights sum to %.3f, expected 1", total)
+	}
+	return mix, nil
+}
+
+// mixReturn returns the --mix portfolio's blended return for a year
+// Returns false when any ticker in the mix has no data for that year
+func mixReturn(md *MarketData, year string) (float64, bool) {
+	blended := 0.0
+	for _, w := range portfolioMix {
+		returns, _ := tickerReturns(md, w.ticker)
+		ret, ok := returns[year]
+		if !ok {
+			return 0, false
+		}
+		blended += ret * w.weight
+	}
+	return blended, true
+}
+
+// calculateMixAverage calculates the --mix portfolio's average over the last 10 complete years
+func calculateMixAverage(md *MarketData) float64 {
+	if md == nil || len(portfolioMix) == 0 {
+		return 0
+	}
+	currentYear := time.Now().Year()
+	sum := 0.0
+	count := 0
+	for year := range md.VOO {
+		yearInt, _ := strconv.Atoi(year)
+		if yearInt >= currentYear-10 && yearInt < currentYear {
+			if ret, ok := mixReturn(md, year); ok {
+				sum += ret
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// mixAverageText returns ", Mix X%" for the market averages line, or "" without --mix
+func mixAverageText(md *MarketData, tickerStyle lipgloss.Style) string {
+	if len(portfolioMix) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %s %.1f%%", tickerStyle.Render("Mix"), calculateMixAverage(md))
+}
+
+// calculateStdDev returns the sample standard deviation of annual returns (0 with fewer than two years)
+func calculateStdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)-1))
+}
+
+// displayMarketData shows historical returns with best/worst years, volatility and averages
 func displayMarketData(md *MarketData) {
+	// Nothing to show without data (e.g. --offline with no cache)
+	if len(md.VOO) == 0 {
+		return
+	}
+
 	// Get sorted years
 	years := make([]string, 0)
 	for year := range md.VOO {
@@ -345,9 +568,16 @@ func displayMarketData(md *MarketData) {
 	rows := [][]string{
 		{"Period", "VOO", "QQQ", "VTI", "BND", "60/40 VTI/BND"},
 	}
+	if len(portfolioMix) > 0 {
+		rows[0] = append(rows[0], "Custom Mix")
+	}
 
-	var vooSum, qqqSum, vtiSum, bndSum float64
-	count := 0
+	// Per-column returns for complete years and the years they're from, used for the summary rows
+	// The custom mix skips years where one of its tickers has no data
+	columns := make([][]float64, len(rows[0])-1)
+	columnYears := make([][]string, len(rows[0])-1)
+	completeYears := make([]string, 0)
+	overridden := false
 
 	for _, year := range years {
 		vooRet := md.VOO[year]
@@ -355,70 +585,77 @@ func displayMarketData(md *MarketData) {
 		vtiRet := md.VTI[year]
 		bndRet := md.BND[year]
 		mix6040 := vtiRet*0.6 + bndRet*0.4
+		yearReturns := []float64{vooRet, qqqRet, vtiRet, bndRet, mix6040}
+		hasData := []bool{true, true, true, true, true}
+		if len(portfolioMix) > 0 {
+			customMix, ok := mixReturn(md, year)
+			yearReturns = append(yearReturns, customMix)
+			hasData = append(hasData, ok)
+		}
 
-		// Only include in average if it's a complete year (not current year)
+		// Only include in the summary rows if it's a complete year (not current year)
 		if year != fmt.Sprintf("%d", time.Now().Year()) {
-			vooSum += vooRet
-			qqqSum += qqqRet
-			vtiSum += vtiRet
-			bndSum += bndRet
-			count++
+			for col, ret := range yearReturns {
+				if hasData[col] {
+					columns[col] = append(columns[col], ret)
+					columnYears[col] = append(columnYears[col], year)
+				}
+			}
+			completeYears = append(completeYears, year)
 		}
 
-		rows = append(rows, []string{
-			"MRKT " + year,
-			fmt.Sprintf("%.2f%%", vooRet),
-			fmt.Sprintf("%.2f%%", qqqRet),
-			fmt.Sprintf("%.2f%%", vtiRet),
-			fmt.Sprintf("%.2f%%", bndRet),
-			fmt.Sprintf("%.2f%%", mix6040),
-		})
-	}
-
-	// Add average row if we have data
-	if count > 0 {
-		avgMix := (vtiSum/float64(count))*0.6 + (bndSum/float64(count))*0.4
-		rows = append(rows, []string{
-			"MRKT Avg",
-			fmt.Sprintf("%.2f%%", vooSum/float64(count)),
-			fmt.Sprintf("%.2f%%", qqqSum/float64(count)),
-			fmt.Sprintf("%.2f%%", vtiSum/float64(count)),
-			fmt.Sprintf("%.2f%%", bndSum/float64(count)),
-			fmt.Sprintf("%.2f%%", avgMix),
-		})
-	}
-
-	// Display using same pattern as other tables
-	re := lipgloss.NewRenderer(os.Stdout)
-	titleStyle := re.NewStyle().Foreground(MonokaiPink).Bold(true)
-	headerStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiCyan).Bold(true)
-	rowStyle := re.NewStyle().Padding(0, 1).Foreground(MonokaiAdaptiveText)
-
-	// Print title
-	fmt.Println()
-	fmt.Println(titleStyle.Render("MARKET DATA"))
-
-	// Create table
-	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(re.NewStyle().Foreground(MonokaiBorder)).
-		Rows(rows...).
-		StyleFunc(func(row, col int) lipgloss.Style {
-			var style lipgloss.Style
-			if row == 0 || row == len(rows)-1 {
-				// Header row and average row (last row)
-				style = headerStyle
-			} else {
-				style = rowStyle
+		row := []string{"MRKT " + year}
+		for col, ret := range yearReturns {
+			if !hasData[col] {
+				row = append(row, "-")
+				continue
 			}
-
-			// Right-align all number columns (col > 0)
-			if col > 0 {
-				style = style.Align(lipgloss.Right)
+			cell := fmt.Sprintf("%.2f%%", ret)
+			if col < 4 && isOverridden(md, rows[0][col+1], year) {
+				cell += "*"
+				overridden = true
 			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	// Add best/worst year, volatility and average rows if we have data
+	if len(completeYears) > 0 {
+		bestRow := []string{"MRKT Best"}
+		worstRow := []string{"MRKT Worst"}
+		stdDevRow := []string{"MRKT Std Dev"}
+		avgRow := []string{"MRKT Avg"}
+		for col, values := range columns {
+			if len(values) == 0 {
+				bestRow = append(bestRow, "-")
+				worstRow = append(worstRow, "-")
+				stdDevRow = append(stdDevRow, "-")
+				avgRow = append(avgRow, "-")
+				continue
+			}
+			best, worst := 0, 0
+			sum := 0.0
+			for i, value := range values {
+				if value > values[best] {
+					best = i
+				}
+				if value < values[worst] {
+					worst = i
+				}
+				sum += value
+			}
+			bestRow = append(bestRow, fmt.Sprintf("%.2f%% (%s)", values[best], columnYears[col][best]))
+			worstRow = append(worstRow, fmt.Sprintf("%.2f%% (%s)", values[worst], columnYears[col][worst]))
+			stdDevRow = append(stdDevRow, fmt.Sprintf("%.2f%%", calculateStdDev(values)))
+			avgRow = append(avgRow, fmt.Sprintf("%.2f%%", sum/float64(len(values))))
+		}
+		rows = append(rows, bestRow, worstRow, stdDevRow, avgRow)
+	}
 
-			return style
-		})
-
-	fmt.Println(t)
+	notes := ""
+	if overridden {
+		notes = "Note: * = manually overridden return (--override), kept across refetches."
+	}
+	displayTable("MARKET DATA", rows, notes, true)
 }
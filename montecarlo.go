@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"calculator/calc"
+)
+
+// tickerReturns returns the year -> annual return map for a ticker symbol
+func tickerReturns(md *MarketData, ticker string) (map[string]float64, bool) {
+	switch strings.ToUpper(ticker) {
+	case "VOO":
+		return md.VOO, true
+	case "QQQ":
+		return md.QQQ, true
+	case "VTI":
+		return md.VTI, true
+	case "BND":
+		return md.BND, true
+	}
+	return nil, false
+}
+
+// completeYearReturns returns annual returns for complete years (excluding the current year)
+func completeYearReturns(returns map[string]float64) []float64 {
+	currentYear := time.Now().Year()
+	years := make([]string, 0, len(returns))
+	for year := range returns {
+		yearInt, err := strconv.Atoi(year)
+		if err == nil && yearInt < currentYear {
+			years = append(years, year)
+		}
+	}
+	sort.Strings(years)
+
+	values := make([]float64, len(years))
+	for i, year := range years {
+		values[i] = returns[year]
+	}
+	return values
+}
+
+// buildBacktestReturns returns consecutive complete-year returns for ticker starting at startYear
+func buildBacktestReturns(md *MarketData, ticker string, startYear int) ([]float64, error) {
+	returns, ok := tickerReturns(md, ticker)
+	if !ok {
+		return nil, fmt.Errorf("unknown ticker %q (expected VOO, QQQ, VTI, or BND)", ticker)
+	}
+
+	currentYear := time.Now().Year()
+	var values []float64
+	for year := startYear; year < currentYear; year++ {
+		ret, ok := returns[strconv.Itoa(year)]
+		if !ok {
+			break
+		}
+		values = append(values, ret)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no %s return data for %d", strings.ToUpper(ticker), startYear)
+	}
+	return values, nil
+}
+
+// percentile returns the p-th percentile (0-100) of sorted values using nearest rank
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(p / 100 * float64(len(sorted)-1))
+	return sorted[index]
+}
+
+// displayMonteCarlo runs trials of renting net worth with bootstrapped annual returns and displays percentiles
+// Costs come from the existing monthly arrays; only investment growth varies per trial
+func displayMonteCarlo(md *MarketData, trials int, ticker string, seed int64) {
+	returns, ok := tickerReturns(md, ticker)
+	if !ok {
+		fmt.Printf("\nMonte Carlo skipped: unknown ticker %q (expected VOO, QQQ, VTI, or BND)\n", ticker)
+		return
+	}
+	history := completeYearReturns(returns)
+	if len(history) == 0 {
+		fmt.Printf("\nMonte Carlo skipped: no historical %s returns available\n", strings.ToUpper(ticker))
+		return
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	periods := getPeriods(config.TotalMonths, config.Include30Year)
+	horizonYears := (len(monthly.BuyingCosts) + 11) / 12
+
+	// results[p] holds renting net worth at period p for every trial
+	results := make([][]float64, len(periods))
+	for p := range results {
+		results[p] = make([]float64, trials)
+	}
+
+	sampled := make([]float64, horizonYears)
+	for trial := 0; trial < trials; trial++ {
+		for year := range sampled {
+			sampled[year] = history[rng.Intn(len(history))]
+		}
+		for p, period := range periods {
+			results[p][trial] = calc.RentingNetWorth(&config, monthly, period.months, config.MonthlyRent, sampled)
+		}
+	}
+
+	// Build table rows (header + data)
+	rows := [][]string{
+		{"Period", "Buying NW", "Renting NW p10", "Renting NW p50", "Renting NW p90", "Rent Wins"},
+	}
+
+	for p, period := range periods {
+		_, _, buyingNetWorth := calculateNetWorth(period.months)
+
+		rentWins := 0
+		for _, v := range results[p] {
+			if v > buyingNetWorth {
+				rentWins++
+			}
+		}
+		sort.Float64s(results[p])
+
+		rows = append(rows, []string{
+			"MC " + period.label,
+			formatCurrency(buyingNetWorth),
+			formatCurrency(percentile(results[p], 10)),
+			formatCurrency(percentile(results[p], 50)),
+			formatCurrency(percentile(results[p], 90)),
+			fmt.Sprintf("%.0f%%", float64(rentWins)/float64(trials)*100),
+		})
+	}
+
+	notes := fmt.Sprintf("Note: %d trials. Each trial samples every year's investment return with replacement from %d years of historical %s returns (seed %d). Buying and renting costs are unchanged; only the renting investment growth varies. 'Rent Wins' = share of trials where renting net worth beats buying net worth.",
+		trials, len(history), strings.ToUpper(ticker), seed)
+	displayTable("MONTE CARLO: RENTING NET WORTH", rows, notes, false)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appConfigDirName is the directory under the user config dir (e.g. ~/.config/rentobuy) holding all saved files
+const appConfigDirName = "rentobuy"
+
+// Saved file locations under the user config dir (or the current directory if that's unavailable), set by resolveConfigPaths
+var (
+	inputsFile     string
+	marketDataFile string
+	profilesDir    string
+	snapshotsDir   string
+)
+
+// legacyFiles maps each file's old name in the current directory to its resolved location, for migration
+var legacyFiles = map[string]*string{
+	".rentobuy_inputs.json":      &inputsFile,
+	".rentobuy_market_data.json": &marketDataFile,
+	".rentobuy_profiles":         &profilesDir,
+}
+
+// resolveConfigPaths sets the saved file locations, creating the config directory if needed
+// Called after flag parsing so --help and invalid flags leave the filesystem untouched
+func resolveConfigPaths() {
+	dir := appConfigDir()
+	configPath := func(name, legacyName string) string {
+		if dir == "" {
+			return legacyName
+		}
+		return filepath.Join(dir, name)
+	}
+	inputsFile = configPath("inputs.json", ".rentobuy_inputs.json")
+	marketDataFile = configPath("market_data.json", ".rentobuy_market_data.json")
+	profilesDir = configPath("profiles", ".rentobuy_profiles")
+	snapshotsDir = configPath("snapshots", ".rentobuy_snapshots")
+}
+
+// appConfigDir returns the app's config directory, creating it if needed
+// Returns "" when the user config dir can't be determined or created
+func appConfigDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(base, appConfigDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ""
+	}
+	return dir
+}
+
+// migrateLegacyFiles moves saved files from the current directory into the config directory on first run
+// Files already present in the config directory are never overwritten; messages go to stderr to keep stdout for results
+func migrateLegacyFiles() {
+	for legacyName, path := range legacyFiles {
+		if *path == legacyName {
+			continue // Config dir unavailable, still using the current directory
+		}
+		if _, err := os.Stat(legacyName); err != nil {
+			continue
+		}
+		if _, err := os.Stat(*path); err == nil {
+			continue
+		}
+		if err := os.Rename(legacyName, *path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not move %s to %s: %v\n", legacyName, *path, err)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Moved %s to %s\n", legacyName, *path)
+	}
+}
@@ -2,13 +2,35 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
-const profilesDir = ".rentobuy_profiles"
+// profileMeta describes a saved profile
+type profileMeta struct {
+	Description string    `json:"description,omitempty"`
+	Created     time.Time `json:"created"`
+	Modified    time.Time `json:"modified"`
+}
+
+// profileFile is the on-disk format of a profile: metadata plus the form inputs
+type profileFile struct {
+	Meta   profileMeta       `json:"meta"`
+	Inputs map[string]string `json:"inputs"`
+}
+
+// profileEntry is a profile name with its metadata, for --list-profiles
+type profileEntry struct {
+	name string
+	meta profileMeta
+	err  error // Set when the profile can't be read; meta is then empty
+}
 
 // ensureProfilesDir creates the profiles directory if it doesn't exist
 func ensureProfilesDir() error {
@@ -39,34 +61,147 @@ func listProfiles() ([]string, error) {
 	return profiles, nil
 }
 
-// loadProfile loads inputs from a named profile
-func loadProfile(name string) (map[string]string, error) {
+// readProfile reads a named profile, accepting both the wrapped format and the older flat inputs map
+// Flat profiles have no metadata, so their dates come from the file's modification time
+func readProfile(name string) (profileFile, error) {
 	profilePath := filepath.Join(profilesDir, name+".json")
 	data, err := os.ReadFile(profilePath)
 	if err != nil {
-		return nil, err
+		return profileFile{}, err
+	}
+
+	var profile profileFile
+	if err := json.Unmarshal(data, &profile); err == nil && profile.Inputs != nil {
+		return profile, nil
 	}
 
 	var inputs map[string]string
-	err = json.Unmarshal(data, &inputs)
+	if err := json.Unmarshal(data, &inputs); err != nil {
+		return profileFile{}, err
+	}
+	profile = profileFile{Inputs: inputs}
+	if info, err := os.Stat(profilePath); err == nil {
+		profile.Meta.Created = info.ModTime()
+		profile.Meta.Modified = info.ModTime()
+	}
+	return profile, nil
+}
+
+// loadProfile loads inputs from a named profile
+func loadProfile(name string) (map[string]string, error) {
+	profile, err := readProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return profile.Inputs, nil
+}
+
+// listProfileEntries returns every profile with its metadata, sorted by name
+// A profile that can't be read is still listed, with its error, so one bad file doesn't hide the rest
+func listProfileEntries() ([]profileEntry, error) {
+	names, err := listProfiles()
 	if err != nil {
 		return nil, err
 	}
 
-	return inputs, nil
+	var entries []profileEntry
+	for _, name := range names {
+		profile, err := readProfile(name)
+		entries = append(entries, profileEntry{name: name, meta: profile.Meta, err: err})
+	}
+	return entries, nil
 }
 
 // saveProfile saves inputs to a named profile
-func saveProfile(name string, inputs map[string]string) error {
+// Overwriting a profile keeps its created date, and its description unless a new one is given
+func saveProfile(name string, inputs map[string]string, description string) error {
 	if err := ensureProfilesDir(); err != nil {
 		return err
 	}
 
+	now := time.Now()
+	profile := profileFile{Meta: profileMeta{Description: description, Created: now}, Inputs: inputs}
+	if existing, err := readProfile(name); err == nil {
+		if !existing.Meta.Created.IsZero() {
+			profile.Meta.Created = existing.Meta.Created
+		}
+		if description == "" {
+			profile.Meta.Description = existing.Meta.Description
+		}
+	}
+	profile.Meta.Modified = now
+
 	profilePath := filepath.Join(profilesDir, name+".json")
-	data, err := json.MarshalIndent(inputs, "", "  ")
+	data, err := json.MarshalIndent(profile, "", "  ")
 	if err != nil {
 		return err
 	}
 
 	return os.WriteFile(profilePath, data, 0644)
 }
+
+// deleteProfile removes a named profile
+func deleteProfile(name string) error {
+	profilePath := filepath.Join(profilesDir, name+".json")
+	if _, err := os.Stat(profilePath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile %q not found", name)
+		}
+		return err
+	}
+
+	return os.Remove(profilePath)
+}
+
+// renameProfile renames a profile, refusing to overwrite an existing target unless force is set
+func renameProfile(oldName, newName string, force bool) error {
+	oldPath := filepath.Join(profilesDir, oldName+".json")
+	newPath := filepath.Join(profilesDir, newName+".json")
+
+	if _, err := os.Stat(oldPath); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("profile %q not found", oldName)
+		}
+		return err
+	}
+
+	if !force {
+		if _, err := os.Stat(newPath); err == nil {
+			return fmt.Errorf("profile %q already exists (use --force to overwrite)", newName)
+		}
+	}
+
+	// os.Rename is atomic within the same directory
+	return os.Rename(oldPath, newPath)
+}
+
+// displayProfileList prints the saved profiles with their created and modified dates and description for --list-profiles
+// Returns an error only when the profiles directory can't be listed
+func displayProfileList() error {
+	entries, err := listProfileEntries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No saved profiles found. Save one with --save-profile or Ctrl+S in the form.")
+		return nil
+	}
+
+	const dateFormat = "2006-01-02 15:04"
+	rows := [][]string{{"Profile", "Created", "Modified", "Description"}}
+	for _, entry := range entries {
+		if entry.err != nil {
+			rows = append(rows, []string{entry.name, "-", "-", "unreadable: " + entry.err.Error()})
+			continue
+		}
+		rows = append(rows, []string{entry.name, entry.meta.Created.Format(dateFormat), entry.meta.Modified.Format(dateFormat), entry.meta.Description})
+	}
+	// Descriptions read better left-aligned
+	displayStyledTable("SAVED PROFILES", rows, "", false, func(row, col int, style lipgloss.Style) lipgloss.Style {
+		if col == 3 {
+			return style.Align(lipgloss.Left)
+		}
+		return style
+	})
+	return nil
+}
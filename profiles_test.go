@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestListProfileEntriesUnreadable(t *testing.T) {
+	savedDir := profilesDir
+	defer func() { profilesDir = savedDir }()
+	profilesDir = t.TempDir()
+
+	if err := saveProfile("good", map[string]string{"purchase_price": "1M"}, "2BR downtown"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(profilesDir, "broken.json"), []byte("{bad"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := listProfileEntries()
+	if err != nil {
+		t.Fatalf("listProfileEntries returned error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].name != "broken" || entries[1].name != "good" {
+		t.Fatalf("listProfileEntries = %v, want broken and good", entries)
+	}
+	if entries[0].err == nil {
+		t.Error("broken profile has no error")
+	}
+	if good := entries[1]; good.err != nil || good.meta.Description != "2BR downtown" || good.meta.Created.IsZero() {
+		t.Errorf("good profile = %+v, want its description and created date", good)
+	}
+}
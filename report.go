@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+	"time"
+)
+
+// htmlReportPath is set by the --html flag
+var htmlReportPath string
+
+// reportSection is one table captured for the HTML report
+type reportSection struct {
+	title            string
+	rows             [][]string
+	notes            string
+	highlightLastRow bool
+}
+
+// reportSections holds every table displayed during this run, in order
+var reportSections []reportSection
+
+// reportCSS styles the HTML report; it's inlined so the file has no external assets
+const reportCSS = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #2d2a2e; margin: 2em auto; max-width: 1100px; padding: 0 1em; }
+h1 { color: #c2185b; }
+h2 { color: #c2185b; font-size: 1.1em; margin-top: 2em; }
+.verdict { font-size: 1.2em; font-weight: bold; color: #d84315; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ddd; padding: 4px 10px; }
+th { background: #f4f4f4; color: #1a5e9a; }
+td:not(:first-child), th:not(:first-child) { text-align: right; }
+tr.total td { font-weight: bold; background: #f4f4f4; }
+p.note { color: #666; font-style: italic; max-width: 900px; }
+`
+
+// recordReportSection captures a displayed table for the HTML report
+func recordReportSection(title string, rows [][]string, notes string, highlightLastRow bool) {
+	reportSections = append(reportSections, reportSection{title, rows, notes, highlightLastRow})
+}
+
+// inputParameterRows returns the scenario's inputs as Group / Input / Value rows, labeled like the form
+func inputParameterRows(isSellVsKeep bool) [][]string {
+	scenario := "buy_vs_rent"
+	if isSellVsKeep {
+		scenario = "sell_vs_keep"
+	}
+
+	rows := [][]string{{"Group", "Input", "Value"}}
+	for _, group := range NewFormModel(currentInputs, nil).groups {
+		if group.Scenario != "both" && group.Scenario != scenario {
+			continue
+		}
+		for _, field := range group.Fields {
+			if strings.HasPrefix(field.Key, "scenario_") {
+				continue // The report title already names the scenario
+			}
+			value := strings.TrimSpace(currentInputs[field.Key])
+			if field.IsToggle {
+				value = "No"
+				if field.Toggled {
+					value = "Yes"
+				}
+			}
+			if value == "" {
+				continue
+			}
+			rows = append(rows, []string{group.Name, field.Label, value})
+		}
+	}
+	return rows
+}
+
+// writeHTMLReport writes the inputs and every recorded table to a self-contained HTML file
+func writeHTMLReport(path string, isSellVsKeep bool, verdict string) error {
+	title := "Buy vs Rent Analysis"
+	if isSellVsKeep {
+		title = "Sell vs Keep Analysis"
+	}
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title), reportCSS)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<p>Generated %s</p>\n", time.Now().Format("January 2, 2006"))
+	fmt.Fprintf(&b, "<p class=\"verdict\">%s</p>\n", html.EscapeString(verdict))
+
+	sections := append([]reportSection{{title: "INPUT PARAMETERS", rows: inputParameterRows(isSellVsKeep)}}, reportSections...)
+	for _, section := range sections {
+		writeHTMLSection(&b, section)
+	}
+	b.WriteString("</body>\n</html>\n")
+
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// writeHTMLSection writes one table with its heading and notes
+func writeHTMLSection(b *strings.Builder, section reportSection) {
+	fmt.Fprintf(b, "<h2>%s</h2>\n<table>\n", html.EscapeString(section.title))
+	for i, row := range section.rows {
+		cellTag := "td"
+		rowClass := ""
+		if i == 0 {
+			cellTag = "th"
+		} else if section.highlightLastRow && i == len(section.rows)-1 {
+			rowClass = " class=\"total\""
+		}
+		fmt.Fprintf(b, "<tr%s>", rowClass)
+		for _, cell := range row {
+			fmt.Fprintf(b, "<%s>%s</%s>", cellTag, html.EscapeString(strings.TrimSpace(cell)), cellTag)
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n")
+
+	for _, paragraph := range strings.Split(section.notes, "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			fmt.Fprintf(b, "<p class=\"note\">%s</p>\n", html.EscapeString(paragraph))
+		}
+	}
+}
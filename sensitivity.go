@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"calculator/calc"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sensitivityVariable is an input that can be swept in the sensitivity grid
+type sensitivityVariable struct {
+	label  string
+	values []float64
+	apply  func(cfg *calc.Inputs, value float64) // Overrides the input in cfg with value
+}
+
+// sensitivityVariables returns the sweepable inputs keyed by name
+// Each apply func is run through rentMinusBuyWith on a copy of the config
+func sensitivityVariables() map[string]sensitivityVariable {
+	return map[string]sensitivityVariable{
+		"appreciation": {
+			label:  "Appreciation",
+			values: []float64{-2, 0, 2, 4, 6, 8},
+			apply:  func(cfg *calc.Inputs, value float64) { cfg.AppreciationRates = []float64{value} },
+		},
+		"investment": {
+			label:  "Investment Return",
+			values: []float64{4, 5, 6, 7, 8, 9, 10},
+			apply: func(cfg *calc.Inputs, value float64) {
+				cfg.InvestmentReturnRates = []float64{value}
+				cfg.InvestmentReturnRate = value
+			},
+		},
+	}
+}
+
+// displaySensitivityGrid displays RENT - BUY at the given horizon across a grid of two variables
+// spec is "rowVariable,colVariable", e.g. "appreciation,investment"
+func displaySensitivityGrid(spec string, months int) {
+	variables := sensitivityVariables()
+	names := strings.Split(spec, ",")
+	if len(names) != 2 || names[0] == names[1] {
+		fmt.Printf("\nSensitivity skipped: expected two different variables (e.g., appreciation,investment), got %q\n", spec)
+		return
+	}
+	rowVar, ok := variables[strings.TrimSpace(names[0])]
+	if !ok {
+		fmt.Printf("\nSensitivity skipped: unknown variable %q (expected appreciation or investment)\n", names[0])
+		return
+	}
+	colVar, ok := variables[strings.TrimSpace(names[1])]
+	if !ok {
+		fmt.Printf("\nSensitivity skipped: unknown variable %q (expected appreciation or investment)\n", names[1])
+		return
+	}
+
+	// Build table rows (header + data)
+	header := []string{rowVar.label + " \\ " + colVar.label}
+	for _, colValue := range colVar.values {
+		header = append(header, fmt.Sprintf("%.1f%%", colValue))
+	}
+	rows := [][]string{header}
+
+	// differences[r][c] tracks the sign of each cell for coloring
+	differences := make([][]float64, len(rowVar.values))
+	for r, rowValue := range rowVar.values {
+		row := []string{fmt.Sprintf("%.1f%%", rowValue)}
+		differences[r] = make([]float64, len(colVar.values))
+		for c, colValue := range colVar.values {
+			difference := rentMinusBuyWith(months, func(cfg *calc.Inputs) {
+				rowVar.apply(cfg, rowValue)
+				colVar.apply(cfg, colValue)
+			})
+			differences[r][c] = difference
+			row = append(row, formatCurrency(difference))
+		}
+		rows = append(rows, row)
+	}
+
+	re := newRenderer()
+	rentWinsStyle := re.NewStyle().Foreground(MonokaiGreen)
+	buyWinsStyle := re.NewStyle().Foreground(MonokaiOrange)
+	cellStyle := func(row, col int, style lipgloss.Style) lipgloss.Style {
+		if row == 0 || col == 0 {
+			return style
+		}
+		if differences[row-1][col-1] > 0 {
+			return style.Inherit(rentWinsStyle)
+		}
+		return style.Inherit(buyWinsStyle)
+	}
+
+	notes := fmt.Sprintf("Note: Each cell is RENT - BUY net worth at %dy with the row and column inputs overridden (all other inputs unchanged). Positive (green) values mean renting wins, negative (orange) values mean buying wins.", months/12)
+	displayStyledTable(fmt.Sprintf("SENSITIVITY: RENT - BUY AT %dY", months/12), rows, notes, false, cellStyle)
+}
+
+// rentMinusBuyWith returns RENT - BUY net worth at months after applying override to a copy of the config
+// The copy gets its own projection, so the current config and monthly are left untouched
+func rentMinusBuyWith(months int, override func(cfg *calc.Inputs)) float64 {
+	cfg := config
+	override(&cfg)
+	p := calc.Project(&cfg)
+
+	_, _, buyingNetWorth := calc.NetWorth(&cfg, p, months)
+	return calc.RentingNetWorth(&cfg, p, months, cfg.MonthlyRent, rentingAnnualReturns(&cfg)) - buyingNetWorth
+}
+
+// tornadoInput is an input perturbed one at a time in the tornado analysis
+type tornadoInput struct {
+	label string
+	scale func(cfg *calc.Inputs, factor float64) // Multiplies the input in cfg by factor; Project derives the rest
+}
+
+// tornadoInputs returns the major inputs perturbed by the tornado analysis
+func tornadoInputs() []tornadoInput {
+	return []tornadoInput{
+		{"Appreciation", func(cfg *calc.Inputs, factor float64) {
+			cfg.AppreciationRates = scaleRates(cfg.AppreciationRates, factor)
+		}},
+		{"Investment Return", func(cfg *calc.Inputs, factor float64) {
+			cfg.InvestmentReturnRates = scaleRates(cfg.InvestmentReturnRates, factor)
+			cfg.InvestmentReturnRate = cfg.InvestmentReturnRates[0]
+		}},
+		{"Inflation", func(cfg *calc.Inputs, factor float64) {
+			cfg.InflationRates = scaleRates(cfg.InflationRates, factor)
+			cfg.InflationRate = cfg.InflationRates[0]
+		}},
+		{"Monthly Rent", func(cfg *calc.Inputs, factor float64) {
+			cfg.MonthlyRent *= factor
+		}},
+		{"Loan Rate", func(cfg *calc.Inputs, factor float64) {
+			if cfg.LoanAmount <= 0 {
+				return
+			}
+			cfg.AnnualRate *= factor
+		}},
+	}
+}
+
+// scaleRates returns a copy of a year-by-year rate schedule with every rate multiplied by factor
+func scaleRates(rates []float64, factor float64) []float64 {
+	scaled := make([]float64, len(rates))
+	for i, rate := range rates {
+		scaled[i] = rate * factor
+	}
+	return scaled
+}
+
+// tornadoPerturbation is the relative change applied to each input in either direction
+const tornadoPerturbation = 0.20
+
+// displayTornado displays how much RENT - BUY at the given horizon swings when each input moves by ±20%
+// Inputs are sorted by swing so the most influential assumption comes first
+func displayTornado(months int) {
+	type tornadoResult struct {
+		label     string
+		low, high float64
+	}
+
+	base := rentMinusBuyWith(months, func(cfg *calc.Inputs) {})
+
+	var results []tornadoResult
+	for _, input := range tornadoInputs() {
+		results = append(results, tornadoResult{
+			label: input.label,
+			low:   rentMinusBuyWith(months, func(cfg *calc.Inputs) { input.scale(cfg, 1-tornadoPerturbation) }),
+			high:  rentMinusBuyWith(months, func(cfg *calc.Inputs) { input.scale(cfg, 1+tornadoPerturbation) }),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return math.Abs(results[i].high-results[i].low) > math.Abs(results[j].high-results[j].low)
+	})
+
+	rows := [][]string{{"Input", "-20%", "+20%", "Swing"}}
+	for _, result := range results {
+		rows = append(rows, []string{
+			result.label,
+			formatCurrency(result.low),
+			formatCurrency(result.high),
+			formatCurrency(math.Abs(result.high - result.low)),
+		})
+	}
+
+	notes := fmt.Sprintf("Note: RENT - BUY net worth at %dy with each input scaled by ±20%% one at a time (baseline %s). Swing is the spread between the two; larger swings mean the result depends more on that assumption.", months/12, formatCurrency(base))
+	displayTable(fmt.Sprintf("TORNADO: RENT - BUY AT %dY", months/12), rows, notes, false)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// takeSnapshot is set by the --snapshot flag
+var takeSnapshot bool
+
+// diffLastSnapshot is set by the --diff-last flag
+var diffLastSnapshot bool
+
+// snapshotTimeFormat names snapshot files so they sort chronologically
+const snapshotTimeFormat = "20060102-150405"
+
+// snapshotPeriod is one period's net worth on each side of the comparison
+type snapshotPeriod struct {
+	Label       string  `json:"label"`
+	Months      int     `json:"months"`
+	Owning      float64 `json:"owning"`      // BUY (BUY vs RENT) or KEEP (SELL vs KEEP) net worth
+	Alternative float64 `json:"alternative"` // RENT or SELL net worth
+	Difference  float64 `json:"difference"`  // Alternative - Owning
+}
+
+// resultSnapshot holds a run's key outputs for comparing against later runs
+type resultSnapshot struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Scenario  string           `json:"scenario"`
+	Verdict   string           `json:"verdict"`
+	Periods   []snapshotPeriod `json:"periods"`
+}
+
+// buildSnapshot collects the per-period net worth and verdict of the current run
+func buildSnapshot(isSellVsKeep bool) resultSnapshot {
+	verdict, _ := verdictLine(isSellVsKeep)
+	snapshot := resultSnapshot{Timestamp: time.Now(), Scenario: "buy_vs_rent", Verdict: verdict}
+	if isSellVsKeep {
+		snapshot.Scenario = "sell_vs_keep"
+	}
+
+	for _, period := range getPeriods(config.TotalMonths, config.Include30Year) {
+		var owning, alternative float64
+		if isSellVsKeep {
+			owning = calculateKeepNetWorth(period.months)
+			alternative = calculateSellNetWorth(period.months)
+		} else {
+			_, _, owning = calculateNetWorth(period.months)
+			alternative = calculateRentingNetWorth(period.months)
+		}
+		owning = deflate(owning, period.months)
+		alternative = deflate(alternative, period.months)
+		snapshot.Periods = append(snapshot.Periods, snapshotPeriod{
+			Label:       strings.TrimSpace(period.label),
+			Months:      period.months,
+			Owning:      owning,
+			Alternative: alternative,
+			Difference:  alternative - owning,
+		})
+	}
+	return snapshot
+}
+
+// saveSnapshot writes the snapshot to a timestamped file in the snapshots directory and returns its path
+func saveSnapshot(snapshot resultSnapshot) (string, error) {
+	if err := os.MkdirAll(snapshotsDir, 0755); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(snapshotsDir, snapshot.Timestamp.Format(snapshotTimeFormat)+".json")
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// loadLastSnapshot reads the most recent snapshot; returns nil if none has been saved yet
+func loadLastSnapshot() (*resultSnapshot, error) {
+	entries, err := os.ReadDir(snapshotsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	sort.Strings(names)
+
+	data, err := os.ReadFile(filepath.Join(snapshotsDir, names[len(names)-1]))
+	if err != nil {
+		return nil, err
+	}
+	var snapshot resultSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// displaySnapshotDiff shows which periods moved since the last snapshot, and by how much
+func displaySnapshotDiff(current resultSnapshot) {
+	last, err := loadLastSnapshot()
+	if err != nil {
+		fmt.Println("Warning: could not read the last snapshot:", err)
+		return
+	}
+	if last == nil {
+		fmt.Println("No snapshot to compare against yet. Run with --snapshot first.")
+		return
+	}
+	if last.Scenario != current.Scenario {
+		fmt.Printf("Warning: the last snapshot (%s) is for a different scenario; skipping the diff\n", last.Timestamp.Format("Jan 2 15:04"))
+		return
+	}
+
+	owningLabel, alternativeLabel := "BUY", "RENT"
+	if current.Scenario == "sell_vs_keep" {
+		owningLabel, alternativeLabel = "KEEP", "SELL"
+	}
+
+	previous := make(map[int]snapshotPeriod)
+	for _, period := range last.Periods {
+		previous[period.Months] = period
+	}
+
+	rows := [][]string{
+		{"Period", owningLabel + " Δ", alternativeLabel + " Δ", alternativeLabel + " - " + owningLabel + " Δ", alternativeLabel + " - " + owningLabel},
+	}
+	for _, period := range current.Periods {
+		before, ok := previous[period.Months]
+		if !ok {
+			continue
+		}
+		owningDelta := period.Owning - before.Owning
+		alternativeDelta := period.Alternative - before.Alternative
+		// Skip periods that only moved by rounding noise
+		if math.Abs(owningDelta) < 0.5 && math.Abs(alternativeDelta) < 0.5 {
+			continue
+		}
+		rows = append(rows, []string{
+			"DIFF " + period.Label,
+			formatCurrency(owningDelta),
+			formatCurrency(alternativeDelta),
+			formatCurrency(period.Difference - before.Difference),
+			formatCurrency(period.Difference),
+		})
+	}
+
+	since := last.Timestamp.Format("Jan 2 15:04")
+	if len(rows) == 1 {
+		fmt.Printf("\nNo periods changed since the last snapshot (%s).\n", since)
+		return
+	}
+
+	notes := fmt.Sprintf("Note: Changes in net worth since the snapshot from %s. Periods that didn't move are omitted.", since)
+	if last.Verdict != current.Verdict {
+		notes += fmt.Sprintf("\n\nVerdict was: %s", last.Verdict)
+	}
+	displayTable("CHANGES SINCE LAST SNAPSHOT", rows, notes, false)
+}